@@ -0,0 +1,50 @@
+// Command fulltext indexes a directory of text files and serves searches over a
+// local HTTP JSON endpoint:
+//
+//	go run ./cmd/fulltext -dir docs -addr localhost:8080
+//	curl 'localhost:8080/search?q=%22wait+group%22+-mutex&ranking=bm25'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"showmeyourcode/go/playground/patternmatching"
+	"showmeyourcode/go/playground/patternmatching/fulltext"
+)
+
+func main() {
+	server, err := newServer(os.Args[1:], os.Stdout)
+	if err == nil {
+		err = server.ListenAndServe()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fulltext:", err)
+		os.Exit(1)
+	}
+}
+
+// newServer indexes the directory given in args and returns the server to run.
+func newServer(args []string, stdout io.Writer) (*http.Server, error) {
+	flags := flag.NewFlagSet("fulltext", flag.ContinueOnError)
+	dir := flags.String("dir", ".", "directory of text files to index")
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	algorithm := flags.String("algo", "kmp", "algorithm confirming phrases")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	algo, err := patternmatching.ParseAlgorithm(*algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := fulltext.IndexDir(*dir, algo)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(stdout, "indexed %d documents of %s, listening on http://%s/search\n", index.Len(), *dir, *addr)
+	return &http.Server{Addr: *addr, Handler: fulltext.NewHandler(index)}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewServerIndexesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("a wait group waits"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	server, err := newServer([]string{"-dir", dir, "-addr", "localhost:0", "-algo", "bm"}, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stdout.String(), "indexed 1 documents") {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/search?q=%22wait+group%22", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"document":"notes.txt"`) {
+		t.Errorf("unexpected response %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestNewServerRejectsUnknownAlgorithm(t *testing.T) {
+	if _, err := newServer([]string{"-algo", "grep"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,199 @@
+// Command pmbench measures the pattern matching algorithms on your own corpora.
+//
+// Every corpus file is searched for every pattern with every algorithm, and the
+// matches, search statistics, ns/op and allocations are written as CSV or JSON:
+//
+//	go run ./cmd/pmbench -corpus logs.txt -patterns patterns.txt -format json -summary
+//
+// The -sweep-alphabet and -sweep-lengths flags add generated scenarios varying
+// the alphabet size and the pattern length.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"showmeyourcode/go/playground/patternmatching"
+	"showmeyourcode/go/playground/patternmatching/benchmark"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "pmbench:", err)
+		os.Exit(1)
+	}
+}
+
+// listFlag collects the values of a flag given several times.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("pmbench", flag.ContinueOnError)
+	var corpora, patternFiles, patterns listFlag
+	flags.Var(&corpora, "corpus", "text file to search (repeatable)")
+	flags.Var(&patternFiles, "patterns", "file with one pattern per line (repeatable)")
+	flags.Var(&patterns, "pattern", "pattern to search for (repeatable)")
+	algorithms := flags.String("algo", "", "comma-separated algorithms or aliases (default: all)")
+	format := flags.String("format", "csv", "report format: csv or json")
+	output := flags.String("output", "", "report file (default: standard output)")
+	summary := flags.Bool("summary", false, "print a table with the winner of every scenario")
+	duration := flags.Duration("duration", 100*time.Millisecond, "minimum measuring time per algorithm and scenario")
+	sweepAlphabet := flags.String("sweep-alphabet", "", "comma-separated alphabet sizes for generated texts, e.g. 2,4,16,64")
+	sweepTextLength := flags.Int("sweep-text-length", 100000, "length of the generated texts")
+	sweepPatternLength := flags.Int("sweep-pattern-length", 8, "pattern length for the alphabet sweep")
+	sweepLengths := flags.String("sweep-lengths", "", "comma-separated pattern lengths taken from every corpus, e.g. 2,8,32")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	config := benchmark.Config{MinDuration: *duration}
+	if *algorithms != "" {
+		for _, name := range strings.Split(*algorithms, ",") {
+			algo, err := patternmatching.ParseAlgorithm(name)
+			if err != nil {
+				return err
+			}
+			config.Algorithms = append(config.Algorithms, algo)
+		}
+	}
+
+	for _, path := range patternFiles {
+		loaded, err := readPatterns(path)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, loaded...)
+	}
+
+	var scenarios []benchmark.Scenario
+	lengths, err := parseInts(*sweepLengths)
+	if err != nil {
+		return fmt.Errorf("-sweep-lengths: %w", err)
+	}
+	for _, path := range corpora {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := filepath.Base(path)
+		for _, pattern := range patterns {
+			scenarios = append(scenarios, benchmark.Scenario{Name: name, Text: string(text), Pattern: pattern})
+		}
+		scenarios = append(scenarios, benchmark.PatternLengthSweep(name, string(text), lengths)...)
+	}
+	sizes, err := parseInts(*sweepAlphabet)
+	if err != nil {
+		return fmt.Errorf("-sweep-alphabet: %w", err)
+	}
+	if len(sizes) > 0 && *sweepTextLength < 1 {
+		return fmt.Errorf("-sweep-text-length: %w", errNotPositive)
+	}
+	if len(sizes) > 0 && *sweepPatternLength < 1 {
+		return fmt.Errorf("-sweep-pattern-length: %w", errNotPositive)
+	}
+	scenarios = append(scenarios, benchmark.AlphabetSweep(*sweepTextLength, *sweepPatternLength, sizes, 1)...)
+	if len(scenarios) == 0 {
+		return errors.New("nothing to measure: give -corpus with -pattern, -patterns or -sweep-lengths, or -sweep-alphabet")
+	}
+
+	results, err := benchmark.Run(scenarios, config)
+	if err != nil {
+		return err
+	}
+
+	if err := writeReport(stdout, *output, *format, results); err != nil {
+		return err
+	}
+
+	if *summary {
+		if *output == "" {
+			fmt.Fprintln(stdout)
+		}
+		return benchmark.WriteSummary(stdout, results)
+	}
+	return nil
+}
+
+// writeReport writes the results to the output file, or to stdout when there is
+// none. The file is closed before returning, so a failed final write is not lost.
+func writeReport(stdout io.Writer, output, format string, results []benchmark.Result) error {
+	report := stdout
+	var file *os.File
+	if output != "" {
+		var err error
+		if file, err = os.Create(output); err != nil {
+			return err
+		}
+		report = file
+	}
+	var err error
+	if format == "json" {
+		err = benchmark.WriteJSON(report, results)
+	} else {
+		err = benchmark.WriteCSV(report, results)
+	}
+	if file != nil {
+		err = errors.Join(err, file.Close())
+	}
+	return err
+}
+
+// readPatterns returns the non-empty lines of the file.
+func readPatterns(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, errors.Join(scanner.Err(), file.Close())
+}
+
+// errNotPositive is returned for a length or size below 1.
+var errNotPositive = errors.New("must be at least 1")
+
+// parseInts parses a comma-separated list of lengths or sizes, all at least 1.
+func parseInts(list string) ([]int, error) {
+	var values []int
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		if value < 1 {
+			return nil, fmt.Errorf("%d: %w", value, errNotPositive)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWritesReportAndSummary(t *testing.T) {
+	dir := t.TempDir()
+	corpus := filepath.Join(dir, "corpus.txt")
+	patterns := filepath.Join(dir, "patterns.txt")
+	if err := os.WriteFile(corpus, []byte("THIS TEST WILL HAVE MULTIPLE MATCHES, SO THAT WE CAN TEST. ONE MORE TEST."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(patterns, []byte("TEST\n\nMORE\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "report.csv")
+
+	var stdout bytes.Buffer
+	err := run([]string{
+		"-corpus", corpus, "-patterns", patterns, "-algo", "kmp,bm",
+		"-sweep-alphabet", "2,4", "-sweep-text-length", "1000", "-duration", "1ms", "-output", report, "-summary",
+	}, &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A header, then 2 patterns and 2 alphabets for 2 algorithms.
+	if len(records) != 1+4*2 {
+		t.Errorf("expected 9 CSV records, got %d", len(records))
+	}
+	if summary := stdout.String(); !strings.Contains(summary, "FASTEST") || !strings.Contains(summary, "alphabet=4") {
+		t.Errorf("unexpected summary:\n%s", summary)
+	}
+}
+
+func TestRunRejectsBadInput(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"-sweep-alphabet", "2", "-algo", "nope"},
+		{"-sweep-alphabet", "2", "-format", "xml"},
+		{"-sweep-alphabet", "two"},
+		{"-sweep-alphabet", "0"},
+		{"-sweep-alphabet", "2", "-sweep-pattern-length", "-1"},
+		{"-sweep-alphabet", "2", "-sweep-text-length", "0"},
+		{"-corpus", "main.go", "-sweep-lengths", "-3"},
+	} {
+		if err := run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
+
+func TestRunReportsWriteErrors(t *testing.T) {
+	// Every write to /dev/full fails with ENOSPC.
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+	err := run([]string{"-sweep-alphabet", "2", "-sweep-text-length", "100", "-duration", "1ms", "-output", "/dev/full"}, &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected the failed write to be reported")
+	}
+}
@@ -2,4 +2,7 @@ module showmeyourcode/go/playground
 
 go 1.23.4
 
-require golang.org/x/exp v0.0.0-20250128182459-e0ece0dbea4c
+require (
+	golang.org/x/exp v0.0.0-20250128182459-e0ece0dbea4c
+	golang.org/x/text v0.22.0
+)
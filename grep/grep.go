@@ -0,0 +1,287 @@
+// Package grep implements the "playground grep" subcommand: a recursive grep
+// searching files with the exact matching algorithms of the patternmatching
+// package.
+//
+//	go run . grep --algo=bm -i -e mutex -e "wait group" samples tasks
+//	go run . grep --algo=kmp,bm,rk,ac --stats -c goroutine .
+//
+// Every hit is printed as file:line:column:text, with lines and byte columns
+// counted from 1. All occurrences are reported, overlapping ones included, so
+// every algorithm finds the same hits.
+package grep
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// ErrNoPattern is returned by Run when neither -e nor a positional pattern is given.
+var ErrNoPattern = errors.New("grep: no pattern")
+
+// listFlag collects the values of a flag given several times.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// config holds the parsed command line.
+type config struct {
+	patterns   []string
+	paths      []string
+	algorithms []patternmatching.Algorithm
+	foldCase   bool
+	count      bool
+	stats      bool
+}
+
+// Run parses the arguments following "grep", searches the given files and
+// directories (the current directory by default) and writes the hits to stdout.
+// It tells whether anything matched.
+func Run(args []string, stdout io.Writer) (bool, error) {
+	c, err := parseArgs(args)
+	if err != nil {
+		return false, err
+	}
+	searchers := make([]*searcher, len(c.algorithms))
+	for i, algo := range c.algorithms {
+		if searchers[i], err = newSearcher(c.patterns, algo, c.foldCase); err != nil {
+			return false, err
+		}
+	}
+
+	out := &output{w: stdout, count: c.count}
+	for _, root := range c.paths {
+		err := walk(root, func(path string, content []byte) {
+			// The hits come from the first algorithm; the others only add to the statistics.
+			hits := searchers[0].search(string(content))
+			for _, s := range searchers[1:] {
+				s.search(string(content))
+			}
+			out.write(path, string(content), hits)
+		})
+		if err != nil {
+			return out.matched, err
+		}
+	}
+
+	if c.stats {
+		if err := writeStats(stdout, searchers); err != nil {
+			return out.matched, err
+		}
+	}
+	return out.matched, out.err
+}
+
+func parseArgs(args []string) (config, error) {
+	flags := flag.NewFlagSet("grep", flag.ContinueOnError)
+	var patterns listFlag
+	flags.Var(&patterns, "e", "pattern to search for (repeatable)")
+	algorithms := flags.String("algo", "kmp", "comma-separated algorithms: kmp, bm, rk, ac or any other name or alias")
+	foldCase := flags.Bool("i", false, "ignore case, with Unicode case folding")
+	count := flags.Bool("c", false, "print only the number of hits of every file")
+	stats := flags.Bool("stats", false, "print the work done by every algorithm")
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	c := config{patterns: patterns, paths: flags.Args(), foldCase: *foldCase, count: *count, stats: *stats}
+	if len(c.patterns) == 0 {
+		if len(c.paths) == 0 {
+			return config{}, ErrNoPattern
+		}
+		c.patterns, c.paths = c.paths[:1], c.paths[1:]
+	}
+	if len(c.paths) == 0 {
+		c.paths = []string{"."}
+	}
+	for _, name := range strings.Split(*algorithms, ",") {
+		algo, err := patternmatching.ParseAlgorithm(name)
+		if err != nil {
+			return config{}, err
+		}
+		c.algorithms = append(c.algorithms, algo)
+	}
+	return c, nil
+}
+
+// hit is an occurrence of one of the patterns in a file.
+type hit struct {
+	start   int
+	pattern int
+}
+
+// searcher finds every pattern with one algorithm and sums the statistics of
+// its searches.
+type searcher struct {
+	algo     patternmatching.Algorithm
+	matchers []patternmatching.Matcher
+	// dictionary finds all the patterns in one pass with Aho-Corasick, which has
+	// no case folding.
+	dictionary *patternmatching.Dictionary
+
+	files int
+	bytes int
+	hits  int
+	// stats sums the searches of all files. The tables of every matcher are
+	// summed once when it is compiled; adding a search keeps that total, which is
+	// at least the tables of any one matcher.
+	stats patternmatching.MatchStats
+}
+
+func newSearcher(patterns []string, algo patternmatching.Algorithm, foldCase bool) (*searcher, error) {
+	s := &searcher{algo: algo}
+	if algo == patternmatching.AhoCorasick && !foldCase {
+		dictionary, err := patternmatching.NewDictionary(patterns)
+		if err != nil {
+			return nil, err
+		}
+		s.dictionary = dictionary
+		// An empty search reports only the tables.
+		_, stats := dictionary.FindAll("")
+		s.stats.AddDistinct(stats)
+		return s, nil
+	}
+	opts := []patternmatching.Option{patternmatching.WithOverlap(patternmatching.Overlapping)}
+	if foldCase {
+		opts = append(opts, patternmatching.WithCaseFolding())
+	}
+	for _, pattern := range patterns {
+		m, err := patternmatching.Compile(pattern, algo, opts...)
+		if err != nil {
+			return nil, err
+		}
+		s.matchers = append(s.matchers, m)
+		_, stats := m.FindAll("")
+		s.stats.AddDistinct(stats)
+	}
+	return s, nil
+}
+
+// search returns the hits in text ordered by offset, then by pattern.
+func (s *searcher) search(text string) []hit {
+	var hits []hit
+	if s.dictionary != nil {
+		matches, stats := s.dictionary.FindAll(text)
+		for _, m := range matches {
+			hits = append(hits, hit{start: m.Start, pattern: m.PatternID})
+		}
+		s.stats.Add(stats)
+	}
+	for i, m := range s.matchers {
+		matches, stats := m.FindAll(text)
+		for _, match := range matches {
+			hits = append(hits, hit{start: match.Start, pattern: i})
+		}
+		s.stats.Add(stats)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].start != hits[j].start {
+			return hits[i].start < hits[j].start
+		}
+		return hits[i].pattern < hits[j].pattern
+	})
+	s.files++
+	s.bytes += len(text)
+	s.hits += len(hits)
+	return hits
+}
+
+// walk calls visit with the content of root, or of every file under it. Hidden
+// directories such as .git are skipped, and so are binary files, those holding
+// a NUL byte.
+func walk(root string, visit func(path string, content []byte)) error {
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(content, 0) < 0 {
+			visit(path, content)
+		}
+		return nil
+	})
+}
+
+// output prints the hits of every file, or their number.
+type output struct {
+	w       io.Writer
+	count   bool
+	matched bool
+	err     error
+}
+
+func (o *output) write(path, text string, hits []hit) {
+	o.matched = o.matched || len(hits) > 0
+	if o.err != nil {
+		return
+	}
+	if o.count {
+		_, o.err = fmt.Fprintf(o.w, "%s:%d\n", path, len(hits))
+		return
+	}
+	line, lineStart := 1, 0
+	for _, h := range hits {
+		// The hits are ordered, so the line is found by moving forward from the last one.
+		for {
+			end := strings.IndexByte(text[lineStart:], '\n')
+			if end < 0 || lineStart+end >= h.start {
+				break
+			}
+			line, lineStart = line+1, lineStart+end+1
+		}
+		lineEnd := len(text)
+		if end := strings.IndexByte(text[lineStart:], '\n'); end >= 0 {
+			lineEnd = lineStart + end
+		}
+		content := strings.TrimSuffix(text[lineStart:lineEnd], "\r")
+		if _, o.err = fmt.Fprintf(o.w, "%s:%d:%d:%s\n", path, line, h.start-lineStart+1, content); o.err != nil {
+			return
+		}
+	}
+}
+
+// writeStats prints a table with the work of every algorithm over all files.
+func writeStats(w io.Writer, searchers []*searcher) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ALGORITHM\tFILES\tBYTES\tHITS\tCOMPARISONS\tPER BYTE\tSHIFTS\tSHIFT DISTANCE\tHASHES\tSPURIOUS HITS\tPREPROCESSING\tTABLE BYTES")
+	for _, s := range searchers {
+		perByte := 0.0
+		if s.bytes > 0 {
+			perByte = float64(s.stats.Comparisons) / float64(s.bytes)
+		}
+		st := s.stats
+		fmt.Fprintf(table, "%s\t%d\t%d\t%d\t%d\t%.3f\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			s.algo, s.files, s.bytes, s.hits, st.Comparisons, perByte,
+			st.Shifts, st.ShiftDistance, st.HashComputations, st.SpuriousHits, st.PreprocessingCost, st.TableMemory)
+	}
+	return table.Flush()
+}
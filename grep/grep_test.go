@@ -0,0 +1,119 @@
+package grep
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates the files under a temporary directory and returns it.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+var tree = map[string]string{
+	"a.txt":         "the mutex guards\nthe map; a Mutex\n",
+	"sub/b.txt":     "no match here\r\nwait group and mutex",
+	".git/c.txt":    "mutex in a hidden directory",
+	"binary.bin":    "mutex\x00",
+	"sub/empty.txt": "",
+}
+
+func TestRunPrintsHits(t *testing.T) {
+	dir := writeTree(t, tree)
+	for _, algo := range []string{"kmp", "bm", "rk", "ac", "two-way"} {
+		var stdout bytes.Buffer
+		matched, err := Run([]string{"--algo=" + algo, "-e", "mutex", "-e", "wait group", dir}, &stdout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := strings.Join([]string{
+			filepath.Join(dir, "a.txt") + ":1:5:the mutex guards",
+			filepath.Join(dir, "sub", "b.txt") + ":2:1:wait group and mutex",
+			filepath.Join(dir, "sub", "b.txt") + ":2:16:wait group and mutex",
+		}, "\n") + "\n"
+		if !matched || stdout.String() != expected {
+			t.Errorf("%s: matched %t, output:\n%s\nexpected:\n%s", algo, matched, stdout.String(), expected)
+		}
+	}
+}
+
+func TestRunIgnoresCase(t *testing.T) {
+	dir := writeTree(t, tree)
+	for _, algo := range []string{"kmp", "ac"} {
+		var stdout bytes.Buffer
+		if _, err := Run([]string{"-i", "-c", "--algo", algo, "MUTEX", filepath.Join(dir, "a.txt")}, &stdout); err != nil {
+			t.Fatal(err)
+		}
+		if expected := filepath.Join(dir, "a.txt") + ":2\n"; stdout.String() != expected {
+			t.Errorf("%s: output %q, expected %q", algo, stdout.String(), expected)
+		}
+	}
+}
+
+func TestRunCountsOverlappingHits(t *testing.T) {
+	dir := writeTree(t, map[string]string{"aaaa.txt": "aaaa\n"})
+	var stdout bytes.Buffer
+	if _, err := Run([]string{"-c", "--algo=bm", "aa", dir}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(dir, "aaaa.txt") + ":3\n"; stdout.String() != expected {
+		t.Errorf("output %q, expected %q", stdout.String(), expected)
+	}
+}
+
+func TestRunReportsStats(t *testing.T) {
+	dir := writeTree(t, tree)
+	var stdout bytes.Buffer
+	if _, err := Run([]string{"--algo=kmp,bm,rk,ac", "--stats", "-c", "mutex", dir}, &stdout); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	// 3 text files, the header, then one row for every algorithm.
+	if len(lines) != 3+1+4 || !strings.HasPrefix(lines[3], "ALGORITHM") {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+	for i, algo := range []string{"knuth-morris-pratt", "boyer-moore", "karp-rabin", "aho-corasick"} {
+		fields := strings.Fields(lines[4+i])
+		// ALGORITHM FILES BYTES HITS ...
+		if fields[0] != algo || fields[1] != "3" || fields[3] != "2" {
+			t.Errorf("unexpected row %q", lines[4+i])
+		}
+	}
+}
+
+func TestRunWithoutMatches(t *testing.T) {
+	dir := writeTree(t, tree)
+	var stdout bytes.Buffer
+	matched, err := Run([]string{"semaphore", dir}, &stdout)
+	if err != nil || matched || stdout.Len() != 0 {
+		t.Errorf("matched %t, err %v, output %q", matched, err, stdout.String())
+	}
+}
+
+func TestRunRejectsBadInput(t *testing.T) {
+	dir := writeTree(t, tree)
+	for _, args := range [][]string{
+		{},
+		{"--algo=nope", "mutex", dir},
+		{"-e", "", dir},
+		{"mutex", filepath.Join(dir, "missing")},
+	} {
+		if _, err := Run(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("%q: expected an error", args)
+		}
+	}
+}
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"golang.org/x/exp/slog"
+	"showmeyourcode/go/playground/grep"
 	"showmeyourcode/go/playground/samples"
 	"showmeyourcode/go/playground/tasks"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "grep" {
+		os.Exit(runGrep(os.Args[2:]))
+	}
+
 	slog.Info("========== Go playground ==========\n")
 	slog.Info("> Below you can see code samples showing Go features.\n")
 	slog.Info("")
@@ -64,3 +72,17 @@ func main() {
 
 	slog.Info("PROGRAM FINISHED")
 }
+
+// runGrep runs the grep subcommand and returns its exit status, as grep(1)
+// does: 0 when something matched, 1 when nothing did and 2 on errors.
+func runGrep(args []string) int {
+	matched, err := grep.Run(args, os.Stdout)
+	switch {
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "playground grep:", err)
+		return 2
+	case !matched:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,211 @@
+package patternmatching
+
+import (
+	"errors"
+)
+
+// ErrNoPatterns is returned by NewDictionary when the dictionary is empty.
+var ErrNoPatterns = errors.New("patternmatching: no patterns")
+
+// DictionaryMatch is an occurrence of one of the dictionary patterns.
+// PatternID is the index of the pattern in the slice passed to NewDictionary.
+type DictionaryMatch struct {
+	PatternID int
+	Start     int
+	End       int
+}
+
+// Dictionary is an Aho-Corasick automaton searching for all patterns of a dictionary
+// in a single pass over the text.
+//
+// The patterns are stored in a trie. Every trie node has a failure link pointing to
+// the node of its longest proper suffix that is also in the trie, and an output link
+// pointing to the nearest node on the failure chain where a pattern ends. Following
+// the failure links on a mismatch means no text character is ever read twice.
+type Dictionary struct {
+	patterns []string
+	nodes    []acNode
+	// tables counts the trie insertions and the steps computing the links, and
+	// the ints and transitions of the nodes.
+	tables preprocessing
+}
+
+type acNode struct {
+	children map[byte]int
+	fail     int
+	// output is the nearest node reachable through failure links that ends a pattern, or -1.
+	output int
+	// patternIDs lists the patterns ending exactly at this node.
+	patternIDs []int
+	depth      int
+}
+
+const acRoot = 0
+
+// NewDictionary builds the automaton for the given patterns.
+func NewDictionary(patterns []string) (*Dictionary, error) {
+	if len(patterns) == 0 {
+		return nil, ErrNoPatterns
+	}
+
+	ac := &Dictionary{
+		patterns: append([]string(nil), patterns...),
+		nodes:    []acNode{{children: map[byte]int{}, output: -1}},
+	}
+	for id, pattern := range patterns {
+		if pattern == "" {
+			return nil, ErrEmptyPattern
+		}
+		ac.insert(id, pattern)
+	}
+	ac.buildLinks()
+	for _, node := range ac.nodes {
+		ac.tables.memory += 3*intBytes + len(node.children)*(1+intBytes) + len(node.patternIDs)*intBytes
+	}
+	return ac, nil
+}
+
+// insert adds the pattern to the trie.
+func (ac *Dictionary) insert(id int, pattern string) {
+	node := acRoot
+	for i := 0; i < len(pattern); i++ {
+		ac.tables.cost++
+		next, ok := ac.nodes[node].children[pattern[i]]
+		if !ok {
+			next = len(ac.nodes)
+			ac.nodes = append(ac.nodes, acNode{children: map[byte]int{}, output: -1, depth: ac.nodes[node].depth + 1})
+			ac.nodes[node].children[pattern[i]] = next
+		}
+		node = next
+	}
+	ac.nodes[node].patternIDs = append(ac.nodes[node].patternIDs, id)
+}
+
+// buildLinks computes failure and output links breadth-first, so the links of
+// every shallower node are known when a node is processed.
+func (ac *Dictionary) buildLinks() {
+	queue := make([]int, 0, len(ac.nodes))
+	for _, child := range ac.nodes[acRoot].children {
+		ac.nodes[child].fail = acRoot
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for char, child := range ac.nodes[current].children {
+			ac.tables.cost++
+			fail := ac.nodes[current].fail
+			for fail != acRoot {
+				ac.tables.cost++
+				if _, ok := ac.nodes[fail].children[char]; ok {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			if next, ok := ac.nodes[fail].children[char]; ok {
+				ac.nodes[child].fail = next
+			} else {
+				ac.nodes[child].fail = acRoot
+			}
+
+			failNode := ac.nodes[ac.nodes[child].fail]
+			if len(failNode.patternIDs) > 0 {
+				ac.nodes[child].output = ac.nodes[child].fail
+			} else {
+				ac.nodes[child].output = failNode.output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// Patterns returns the dictionary the automaton was built from.
+func (ac *Dictionary) Patterns() []string {
+	return append([]string(nil), ac.patterns...)
+}
+
+// FindAll returns every occurrence of every pattern, ordered by end offset.
+// Occurrences ending at the same offset are reported longest pattern first.
+func (ac *Dictionary) FindAll(text string) ([]DictionaryMatch, MatchStats) {
+	var matches []DictionaryMatch
+	stats := ac.FindIter(text, func(match DictionaryMatch) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches, stats
+}
+
+// FindIter calls yield for every occurrence until yield returns false.
+// Every lookup of a trie transition counts as one comparison. Following a
+// failure link shifts the patterns by the depth it drops, and a byte without a
+// transition from the root shifts them past it.
+func (ac *Dictionary) FindIter(text string, yield func(DictionaryMatch) bool) MatchStats {
+	s := search{stats: ac.tables.stats()}
+	ac.scan(text, &s, yield)
+	return s.stats
+}
+
+// scan runs the automaton over text. The patterns are aligned so that the
+// current node spells the text before i; a transition compares text[i] with the
+// pattern byte at the depth of the node.
+func (ac *Dictionary) scan(text string, s *search, yield func(DictionaryMatch) bool) {
+	node := acRoot
+
+	for i := 0; i < len(text); i++ {
+		for {
+			s.stats.Comparisons++
+			depth := ac.nodes[node].depth
+			next, ok := ac.nodes[node].children[text[i]]
+			if len(ac.nodes[node].children) > 0 {
+				// A node ending the longest pattern has nothing to compare with.
+				s.compare(i-depth, i, depth, ok)
+			}
+			if ok {
+				node = next
+				break
+			}
+			if node == acRoot {
+				s.shift(i, i+1, ShiftNext)
+				break
+			}
+			fail := ac.nodes[node].fail
+			s.shift(i-depth, i-ac.nodes[fail].depth, ShiftFailureLink)
+			node = fail
+		}
+
+		for out := node; out != -1; out = ac.nodes[out].output {
+			depth := ac.nodes[out].depth
+			for _, id := range ac.nodes[out].patternIDs {
+				if !yield(DictionaryMatch{PatternID: id, Start: i + 1 - depth, End: i + 1}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ahoCorasickScanner exposes a single-pattern automaton through the Matcher interface.
+type ahoCorasickScanner struct {
+	automaton *Dictionary
+}
+
+func newAhoCorasick(pattern string) scanner {
+	automaton, err := NewDictionary([]string{pattern})
+	if err != nil {
+		// Compile rejects empty patterns before the scanner is built.
+		panic(err)
+	}
+	return ahoCorasickScanner{automaton: automaton}
+}
+
+func (a ahoCorasickScanner) tables() preprocessing {
+	return a.automaton.tables
+}
+
+func (a ahoCorasickScanner) scan(text string, s *search) {
+	a.automaton.scan(text, s, func(match DictionaryMatch) bool {
+		return s.report(match.Start)
+	})
+}
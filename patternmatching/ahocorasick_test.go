@@ -0,0 +1,65 @@
+package patternmatching
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDictionaryFindAll(t *testing.T) {
+	ac, err := NewDictionary([]string{"he", "she", "his", "hers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	matches, stats := ac.FindAll("ushers")
+	expected := []DictionaryMatch{
+		{PatternID: 1, Start: 1, End: 4}, // she
+		{PatternID: 0, Start: 2, End: 4}, // he
+		{PatternID: 3, Start: 2, End: 6}, // hers
+	}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected %v, got %v", expected, matches)
+	}
+	if stats.Comparisons < len("ushers") {
+		t.Errorf("expected at least one comparison per character, got %d", stats.Comparisons)
+	}
+}
+
+func TestDictionaryMatchesBruteForce(t *testing.T) {
+	patterns := []string{"TEST", "ES", "T", "MORE TEST", "TEST", "XYZ"}
+	text := "THIS TEST WILL HAVE MULTIPLE MATCHES, SO THAT WE CAN TEST. ONE MORE TEST."
+
+	ac, err := NewDictionary(patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[DictionaryMatch]bool)
+	matches, _ := ac.FindAll(text)
+	for _, match := range matches {
+		found[match] = true
+	}
+
+	expected := 0
+	for id, pattern := range patterns {
+		positions, _ := MustCompile(pattern, BruteForce).FindAll(text)
+		for _, position := range positions {
+			expected++
+			if !found[DictionaryMatch{PatternID: id, Start: position.Start, End: position.End}] {
+				t.Errorf("missing %q at %d", pattern, position.Start)
+			}
+		}
+	}
+	if len(matches) != expected {
+		t.Errorf("expected %d matches, got %d", expected, len(matches))
+	}
+}
+
+func TestDictionaryErrors(t *testing.T) {
+	if _, err := NewDictionary(nil); !errors.Is(err, ErrNoPatterns) {
+		t.Errorf("expected ErrNoPatterns, got %v", err)
+	}
+	if _, err := NewDictionary([]string{"a", ""}); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package alignment
+
+import (
+	"math"
+	"strings"
+)
+
+// Gap is the byte standing for a gap in aligned sequences.
+const Gap = '-'
+
+// Alignment is an alignment of two sequences. A and B have the same length;
+// column i aligns A[i] with B[i], either of which may be Gap. The aligned parts
+// are a[StartA:EndA] and b[StartB:EndB], the whole sequences for a global
+// alignment.
+type Alignment struct {
+	A, B   string
+	Score  int
+	StartA int
+	EndA   int
+	StartB int
+	EndB   int
+}
+
+// String returns the aligned sequences on two lines with a line between them
+// marking matches with '|' and mismatches with '.'.
+func (al Alignment) String() string {
+	var middle strings.Builder
+	for i := 0; i < len(al.A); i++ {
+		switch {
+		case al.A[i] == Gap || al.B[i] == Gap:
+			middle.WriteByte(' ')
+		case al.A[i] == al.B[i]:
+			middle.WriteByte('|')
+		default:
+			middle.WriteByte('.')
+		}
+	}
+	return al.A + "\n" + middle.String() + "\n" + al.B
+}
+
+// Global returns the best scoring alignment of the whole of a with the whole of
+// b (Needleman-Wunsch, with Gotoh's three matrices for affine gaps). It takes
+// O(len(a) * len(b)) time and space; Hirschberg finds an alignment of the same
+// score in linear space.
+func Global(a, b string, scoring Scoring) (Alignment, error) {
+	if err := scoring.validate(); err != nil {
+		return Alignment{}, err
+	}
+	return align(a, b, scoring, false), nil
+}
+
+// Local returns the best scoring alignment of a substring of a with a substring
+// of b (Smith-Waterman, with affine gaps as in Global). An empty alignment
+// scores 0, so the result never scores less.
+func Local(a, b string, scoring Scoring) (Alignment, error) {
+	if err := scoring.validate(); err != nil {
+		return Alignment{}, err
+	}
+	return align(a, b, scoring, true), nil
+}
+
+// The states of the dynamic programming: an alignment ending with a pair of
+// bytes, with a byte of a against a gap, or with a gap against a byte of b.
+// start marks the beginning of a local alignment.
+const (
+	pair byte = iota
+	gapInB
+	gapInA
+	start
+)
+
+// negInf stands for impossible alignments; adding penalties to it cannot overflow.
+const negInf = math.MinInt / 4
+
+func align(a, b string, scoring Scoring, local bool) Alignment {
+	n, m := len(a), len(b)
+	open, extend := scoring.GapOpen, scoring.GapExtend
+	// The scores of the three states are kept for two rows; trace remembers the
+	// previous state of every state of every cell.
+	var score, previous [3][]int
+	for state := range score {
+		score[state] = make([]int, m+1)
+		previous[state] = make([]int, m+1)
+	}
+	trace := make([][3]byte, (n+1)*(m+1))
+	cell := func(i, j int) *[3]byte { return &trace[i*(m+1)+j] }
+
+	// best picks the highest of the three states, preferring pair, then gapInB.
+	best := func(candidates [3]int) (int, byte) {
+		state := pair
+		for s := gapInB; s <= gapInA; s++ {
+			if candidates[s] > candidates[state] {
+				state = s
+			}
+		}
+		return candidates[state], state
+	}
+
+	bestScore, bestI, bestJ := 0, 0, 0
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			var p, x, y int
+			t := cell(i, j)
+			switch {
+			case i == 0 && j == 0, local && (i == 0 || j == 0):
+				// A local alignment may start anywhere, but never with a gap.
+				p, x, y = 0, negInf, negInf
+				t[pair] = start
+			case i == 0:
+				p, x, y = negInf, negInf, scoring.gap(j)
+				t[gapInA] = gapInA
+				if j == 1 {
+					t[gapInA] = pair
+				}
+			case j == 0:
+				p, x, y = negInf, scoring.gap(i), negInf
+				t[gapInB] = gapInB
+				if i == 1 {
+					t[gapInB] = pair
+				}
+			default:
+				diagonal, from := best([3]int{previous[pair][j-1], previous[gapInB][j-1], previous[gapInA][j-1]})
+				if local && diagonal <= 0 {
+					diagonal, from = 0, start
+				}
+				p, t[pair] = diagonal+scoring.Matrix.Score(a[i-1], b[j-1]), from
+				x, t[gapInB] = best([3]int{previous[pair][j] + open + extend, previous[gapInB][j] + extend, previous[gapInA][j] + open + extend})
+				y, t[gapInA] = best([3]int{score[pair][j-1] + open + extend, score[gapInB][j-1] + open + extend, score[gapInA][j-1] + extend})
+			}
+			score[pair][j], score[gapInB][j], score[gapInA][j] = p, x, y
+			if local && p > bestScore {
+				bestScore, bestI, bestJ = p, i, j
+			}
+		}
+		if i < n {
+			score, previous = previous, score
+		}
+	}
+
+	i, j, state := n, m, pair
+	if local {
+		i, j = bestI, bestJ
+	} else {
+		bestScore, state = best([3]int{score[pair][m], score[gapInB][m], score[gapInA][m]})
+	}
+	endA, endB := i, j
+
+	var alignedA, alignedB []byte
+	for i > 0 || j > 0 {
+		from := cell(i, j)[state]
+		switch state {
+		case pair:
+			i, j = i-1, j-1
+			alignedA, alignedB = append(alignedA, a[i]), append(alignedB, b[j])
+		case gapInB:
+			i--
+			alignedA, alignedB = append(alignedA, a[i]), append(alignedB, Gap)
+		case gapInA:
+			j--
+			alignedA, alignedB = append(alignedA, Gap), append(alignedB, b[j])
+		}
+		if from == start {
+			break
+		}
+		state = from
+	}
+	reverse(alignedA)
+	reverse(alignedB)
+	return Alignment{
+		A: string(alignedA), B: string(alignedB), Score: bestScore,
+		StartA: i, EndA: endA, StartB: j, EndB: endB,
+	}
+}
+
+func reverse(s []byte) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,160 @@
+package alignment
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// rescore checks that al is well formed and returns its score under scoring.
+func rescore(t *testing.T, al Alignment, scoring Scoring) int {
+	t.Helper()
+	if len(al.A) != len(al.B) {
+		t.Fatalf("aligned sequences differ in length: %q, %q", al.A, al.B)
+	}
+	for i := 0; i < len(al.A); i++ {
+		if al.A[i] == Gap && al.B[i] == Gap {
+			t.Fatalf("a column of two gaps in %q, %q", al.A, al.B)
+		}
+	}
+	return scoreOf(al.A, al.B, scoring)
+}
+
+// scoreOf returns the score of the aligned sequences a and b.
+func scoreOf(a, b string, scoring Scoring) int {
+	score, gapA, gapB := 0, 0, 0
+	for i := 0; i < len(a); i++ {
+		switch {
+		case a[i] == Gap:
+			score += scoring.GapExtend
+			if gapA == 0 {
+				score += scoring.GapOpen
+			}
+			gapA, gapB = gapA+1, 0
+		case b[i] == Gap:
+			score += scoring.GapExtend
+			if gapB == 0 {
+				score += scoring.GapOpen
+			}
+			gapA, gapB = 0, gapB+1
+		default:
+			score += scoring.Matrix.Score(a[i], b[i])
+			gapA, gapB = 0, 0
+		}
+	}
+	return score
+}
+
+// bestAlignment tries every alignment of a and b and returns the best score.
+func bestAlignment(a, b string, scoring Scoring) int {
+	best := 0
+	first := true
+	var walk func(i, j int, alignedA, alignedB []byte)
+	walk = func(i, j int, alignedA, alignedB []byte) {
+		if i == len(a) && j == len(b) {
+			score := scoreOf(string(alignedA), string(alignedB), scoring)
+			if first || score > best {
+				best, first = score, false
+			}
+			return
+		}
+		if i < len(a) && j < len(b) {
+			walk(i+1, j+1, append(alignedA, a[i]), append(alignedB, b[j]))
+		}
+		if i < len(a) {
+			walk(i+1, j, append(alignedA, a[i]), append(alignedB, Gap))
+		}
+		if j < len(b) {
+			walk(i, j+1, append(alignedA, Gap), append(alignedB, b[j]))
+		}
+	}
+	walk(0, 0, nil, nil)
+	return best
+}
+
+func stripGaps(s string) string {
+	return strings.ReplaceAll(s, string(Gap), "")
+}
+
+func TestGlobalTextbookExample(t *testing.T) {
+	al, err := Global("GATTACA", "GCATGCU", SimpleScoring(1, -1, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.Score != 0 || stripGaps(al.A) != "GATTACA" || stripGaps(al.B) != "GCATGCU" {
+		t.Errorf("unexpected alignment %+v", al)
+	}
+	if rescore(t, al, SimpleScoring(1, -1, -1)) != al.Score {
+		t.Errorf("the alignment does not score %d:\n%s", al.Score, al)
+	}
+}
+
+func TestLocalTextbookExample(t *testing.T) {
+	al, err := Local("TGTTACGG", "GGTTGACTA", SimpleScoring(3, -3, -2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.Score != 13 || al.A != "GTT-AC" || al.B != "GTTGAC" {
+		t.Errorf("expected GTT-AC / GTTGAC scoring 13, got %+v", al)
+	}
+	if "TGTTACGG"[al.StartA:al.EndA] != "GTTAC" || "GGTTGACTA"[al.StartB:al.EndB] != "GTTGAC" {
+		t.Errorf("unexpected offsets %+v", al)
+	}
+}
+
+func TestAffineGapsPreferOneLongGap(t *testing.T) {
+	scoring := Scoring{Matrix: NewMatrix(2, -3), GapOpen: -5, GapExtend: -1}
+	al, err := Global("AAAGGGTTT", "AAATTT", scoring)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if al.A != "AAAGGGTTT" || al.B != "AAA---TTT" || al.Score != 12-8 {
+		t.Errorf("expected a single gap of 3, got %+v", al)
+	}
+}
+
+func TestAlignmentsMatchExhaustiveSearch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	scorings := []Scoring{
+		SimpleScoring(1, -1, -1),
+		SimpleScoring(2, -1, -2),
+		{Matrix: NewMatrix(3, -2), GapOpen: -3, GapExtend: -1},
+	}
+	for round := 0; round < 200; round++ {
+		a, b := randomString(random, random.Intn(6), "ACG"), randomString(random, random.Intn(6), "ACG")
+		for _, scoring := range scorings {
+			al, err := Global(a, b, scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := bestAlignment(a, b, scoring); al.Score != expected {
+				t.Fatalf("%q, %q, %+v: expected %d, got %+v", a, b, scoring, expected, al)
+			}
+			if stripGaps(al.A) != a || stripGaps(al.B) != b || rescore(t, al, scoring) != al.Score {
+				t.Fatalf("%q, %q: inconsistent alignment %+v", a, b, al)
+			}
+
+			local, err := Local(a, b, scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := 0
+			for i := 0; i <= len(a); i++ {
+				for j := i + 1; j <= len(a); j++ {
+					for k := 0; k <= len(b); k++ {
+						for l := k + 1; l <= len(b); l++ {
+							expected = max(expected, bestAlignment(a[i:j], b[k:l], scoring))
+						}
+					}
+				}
+			}
+			if local.Score != expected {
+				t.Fatalf("%q, %q, %+v: expected a local score of %d, got %+v", a, b, scoring, expected, local)
+			}
+			if stripGaps(local.A) != a[local.StartA:local.EndA] || stripGaps(local.B) != b[local.StartB:local.EndB] ||
+				rescore(t, local, scoring) != local.Score {
+				t.Fatalf("%q, %q: inconsistent local alignment %+v", a, b, local)
+			}
+		}
+	}
+}
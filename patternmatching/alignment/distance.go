@@ -0,0 +1,114 @@
+// Package alignment measures how similar two sequences are and shows how they
+// line up: edit distances, the longest common subsequence, and global
+// (Needleman-Wunsch) and local (Smith-Waterman) alignments with configurable
+// scoring. Sequences are strings compared byte by byte.
+package alignment
+
+// Levenshtein returns the minimum number of single byte insertions, deletions
+// and substitutions turning a into b. It keeps two rows of the dynamic
+// programming table, so it takes O(len(a) * len(b)) time and O(len(b)) space.
+func Levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// DamerauLevenshtein is Levenshtein with transpositions of two adjacent bytes
+// as a fourth operation. Unlike the restricted "optimal string alignment"
+// variant, a transposed pair may be edited further, so the result is a metric:
+// DamerauLevenshtein("ca", "abc") is 2 (ca -> ac -> abc).
+//
+// This is the algorithm of Lowrance and Wagner, which remembers for every byte
+// the last row it occurred in.
+func DamerauLevenshtein(a, b string) int {
+	n, m := len(a), len(b)
+	infinity := n + m
+	// d is offset by one row and column holding infinity, so d[i+1][j+1] is the
+	// distance between a[:i] and b[:j].
+	d := make([][]int, n+2)
+	for i := range d {
+		d[i] = make([]int, m+2)
+	}
+	d[0][0] = infinity
+	for i := 0; i <= n; i++ {
+		d[i+1][0] = infinity
+		d[i+1][1] = i
+	}
+	for j := 0; j <= m; j++ {
+		d[0][j+1] = infinity
+		d[1][j+1] = j
+	}
+
+	var lastRow [256]int
+	for i := 1; i <= n; i++ {
+		lastColumn := 0
+		for j := 1; j <= m; j++ {
+			k := lastRow[b[j-1]]
+			l := lastColumn
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+				lastColumn = j
+			}
+			d[i+1][j+1] = min(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+				// Transpose a[k-1] and a[i-1], deleting and inserting what lies between.
+				d[k][l]+(i-k-1)+1+(j-l-1),
+			)
+		}
+		lastRow[a[i-1]] = i
+	}
+	return d[n+1][m+1]
+}
+
+// LCS returns a longest common subsequence of a and b: the longest sequence of
+// bytes occurring in both in the same order, not necessarily adjacent. When
+// several exist, the one ending with the latest bytes of a is returned.
+func LCS(a, b string) string {
+	n, m := len(a), len(b)
+	// length[i][j] is the length of an LCS of a[i:] and b[j:].
+	length := make([][]int, n+1)
+	for i := range length {
+		length[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				length[i][j] = length[i+1][j+1] + 1
+			} else {
+				length[i][j] = max(length[i+1][j], length[i][j+1])
+			}
+		}
+	}
+
+	lcs := make([]byte, 0, length[0][0])
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case a[i] == b[j]:
+			lcs = append(lcs, a[i])
+			i++
+			j++
+		case length[i+1][j] >= length[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return string(lcs)
+}
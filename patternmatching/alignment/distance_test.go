@@ -0,0 +1,122 @@
+package alignment
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randomString(random *rand.Rand, n int, alphabet string) string {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = alphabet[random.Intn(len(alphabet))]
+	}
+	return string(s)
+}
+
+// naiveLevenshtein tries every edit recursively.
+func naiveLevenshtein(a, b string) int {
+	if a == "" || b == "" {
+		return len(a) + len(b)
+	}
+	cost := 1
+	if a[0] == b[0] {
+		cost = 0
+	}
+	return min(naiveLevenshtein(a[1:], b)+1, naiveLevenshtein(a, b[1:])+1, naiveLevenshtein(a[1:], b[1:])+cost)
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"ab", "ba", 2},
+	}
+	for _, tt := range tests {
+		if d := Levenshtein(tt.a, tt.b); d != tt.expected {
+			t.Errorf("%q, %q: expected %d, got %d", tt.a, tt.b, tt.expected, d)
+		}
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		a, b := randomString(random, random.Intn(7), "abc"), randomString(random, random.Intn(7), "abc")
+		if d, expected := Levenshtein(a, b), naiveLevenshtein(a, b); d != expected {
+			t.Fatalf("%q, %q: expected %d, got %d", a, b, expected, d)
+		}
+	}
+}
+
+func TestDamerauLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"ab", "ba", 1},
+		{"ca", "abc", 2},
+		{"abcdef", "abdcef", 1},
+		{"kitten", "sitting", 3},
+		{"a cat", "an act", 2},
+	}
+	for _, tt := range tests {
+		if d := DamerauLevenshtein(tt.a, tt.b); d != tt.expected {
+			t.Errorf("%q, %q: expected %d, got %d", tt.a, tt.b, tt.expected, d)
+		}
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		a, b, c := randomString(random, random.Intn(8), "abc"), randomString(random, random.Intn(8), "abc"), randomString(random, random.Intn(8), "abc")
+		ab := DamerauLevenshtein(a, b)
+		if ab > Levenshtein(a, b) || ab != DamerauLevenshtein(b, a) {
+			t.Fatalf("%q, %q: distance %d is larger than Levenshtein or not symmetric", a, b, ab)
+		}
+		if ac := DamerauLevenshtein(a, c); ac > ab+DamerauLevenshtein(b, c) {
+			t.Fatalf("%q, %q, %q: the triangle inequality does not hold", a, b, c)
+		}
+	}
+}
+
+// isSubsequence tells whether s can be obtained by deleting bytes of t.
+func isSubsequence(s, t string) bool {
+	i := 0
+	for j := 0; i < len(s) && j < len(t); j++ {
+		if s[i] == t[j] {
+			i++
+		}
+	}
+	return i == len(s)
+}
+
+func TestLCS(t *testing.T) {
+	if lcs := LCS("AGGTAB", "GXTXAYB"); lcs != "GTAB" {
+		t.Errorf("expected GTAB, got %q", lcs)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		a, b := randomString(random, random.Intn(9), "abc"), randomString(random, random.Intn(9), "abc")
+		// Every subsequence of a, longest first.
+		longest := 0
+		for mask := 0; mask < 1<<len(a); mask++ {
+			var sub []byte
+			for i := range a {
+				if mask&(1<<i) != 0 {
+					sub = append(sub, a[i])
+				}
+			}
+			if len(sub) > longest && isSubsequence(string(sub), b) {
+				longest = len(sub)
+			}
+		}
+		lcs := LCS(a, b)
+		if len(lcs) != longest || !isSubsequence(lcs, a) || !isSubsequence(lcs, b) {
+			t.Fatalf("%q, %q: expected a common subsequence of length %d, got %q", a, b, longest, lcs)
+		}
+	}
+}
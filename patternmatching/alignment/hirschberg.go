@@ -0,0 +1,80 @@
+package alignment
+
+import (
+	"errors"
+)
+
+// ErrAffineGaps is returned by Hirschberg, which only supports linear gap penalties.
+var ErrAffineGaps = errors.New("alignment: linear-space alignment requires GapOpen = 0")
+
+// hirschbergCutoff is the size below which Hirschberg aligns with Global, whose
+// table is small enough by then.
+const hirschbergCutoff = 1 << 10
+
+// Hirschberg returns a global alignment with the same score as Global in
+// O(len(a) + len(b)) space, filling about twice as many cells. It splits a in
+// half, finds where the best alignment crosses that row by scoring the first
+// half forwards and the second half backwards, and aligns both parts recursively.
+func Hirschberg(a, b string, scoring Scoring) (Alignment, error) {
+	if err := scoring.validate(); err != nil {
+		return Alignment{}, err
+	}
+	if scoring.GapOpen != 0 {
+		return Alignment{}, ErrAffineGaps
+	}
+	var alignedA, alignedB []byte
+	score := hirschberg(a, b, scoring, &alignedA, &alignedB)
+	return Alignment{
+		A: string(alignedA), B: string(alignedB), Score: score,
+		EndA: len(a), EndB: len(b),
+	}, nil
+}
+
+// hirschberg appends the alignment of a and b and returns its score.
+func hirschberg(a, b string, scoring Scoring, alignedA, alignedB *[]byte) int {
+	if len(a) <= 1 || len(b) <= 1 || (len(a)+1)*(len(b)+1) <= hirschbergCutoff {
+		al := align(a, b, scoring, false)
+		*alignedA = append(*alignedA, al.A...)
+		*alignedB = append(*alignedB, al.B...)
+		return al.Score
+	}
+
+	middle := len(a) / 2
+	forward := lastRow(a[:middle], b, scoring, false)
+	backward := lastRow(a[middle:], b, scoring, true)
+	split := 0
+	for j := 1; j <= len(b); j++ {
+		if forward[j]+backward[len(b)-j] > forward[split]+backward[len(b)-split] {
+			split = j
+		}
+	}
+	return hirschberg(a[:middle], b[:split], scoring, alignedA, alignedB) +
+		hirschberg(a[middle:], b[split:], scoring, alignedA, alignedB)
+}
+
+// lastRow returns the scores of aligning the whole of a with every prefix of b,
+// or with every suffix of b when reversed is set, in which case a and b are
+// both read backwards.
+func lastRow(a, b string, scoring Scoring, reversed bool) []int {
+	at := func(s string, i int) byte {
+		if reversed {
+			return s[len(s)-1-i]
+		}
+		return s[i]
+	}
+	gap := scoring.GapExtend
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j * gap
+	}
+	for i := 0; i < len(a); i++ {
+		diagonal := row[0]
+		row[0] += gap
+		for j := 1; j <= len(b); j++ {
+			up := row[j]
+			row[j] = max(diagonal+scoring.Matrix.Score(at(a, i), at(b, j-1)), up+gap, row[j-1]+gap)
+			diagonal = up
+		}
+	}
+	return row
+}
@@ -0,0 +1,56 @@
+package alignment
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestHirschbergMatchesGlobalScore(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	scorings := []Scoring{SimpleScoring(1, -1, -1), SimpleScoring(5, -4, -6), {Matrix: DNA(), GapExtend: -3}}
+	for round := 0; round < 30; round++ {
+		// Long enough to split several times before reaching the cutoff.
+		a := randomString(random, random.Intn(300), "ACGT")
+		b := randomString(random, random.Intn(300), "ACGT")
+		for _, scoring := range scorings {
+			expected, err := Global(a, b, scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			al, err := Hirschberg(a, b, scoring)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if al.Score != expected.Score || rescore(t, al, scoring) != al.Score {
+				t.Fatalf("%q, %q: expected score %d, got %d", a, b, expected.Score, al.Score)
+			}
+			if stripGaps(al.A) != a || stripGaps(al.B) != b || al.EndA != len(a) || al.EndB != len(b) {
+				t.Fatalf("%q, %q: the alignment does not cover the sequences: %+v", a, b, al)
+			}
+		}
+	}
+}
+
+func TestHirschbergRejectsAffineGaps(t *testing.T) {
+	if _, err := Hirschberg("a", "b", Scoring{Matrix: DNA(), GapOpen: -10, GapExtend: -1}); !errors.Is(err, ErrAffineGaps) {
+		t.Errorf("expected ErrAffineGaps, got %v", err)
+	}
+}
+
+func BenchmarkLongAlignment(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	x := randomString(random, 3000, "ACGT")
+	y := randomString(random, 3000, "ACGT")
+	scoring := Scoring{Matrix: DNA(), GapExtend: -3}
+	b.Run("global", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Global(x, y, scoring)
+		}
+	})
+	b.Run("hirschberg", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Hirschberg(x, y, scoring)
+		}
+	})
+}
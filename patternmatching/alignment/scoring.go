@@ -0,0 +1,148 @@
+package alignment
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidMatrix is returned by ParseMatrix for malformed input.
+	ErrInvalidMatrix = errors.New("alignment: invalid scoring matrix")
+	// ErrInvalidScoring is returned for a Scoring without a matrix, or with a gap
+	// penalty which does not lower the score.
+	ErrInvalidScoring = errors.New("alignment: invalid scoring")
+)
+
+// Matrix holds the score of aligning every pair of bytes.
+type Matrix struct {
+	scores [256][256]int
+}
+
+// NewMatrix returns a matrix scoring match for equal bytes and mismatch for the
+// others.
+func NewMatrix(match, mismatch int) *Matrix {
+	m := &Matrix{}
+	for a := range m.scores {
+		for b := range m.scores[a] {
+			m.scores[a][b] = mismatch
+		}
+		m.scores[a][a] = match
+	}
+	return m
+}
+
+// Score returns the score of aligning a with b.
+func (m *Matrix) Score(a, b byte) int {
+	return m.scores[a][b]
+}
+
+// Set changes the score of aligning a with b, and b with a.
+func (m *Matrix) Set(a, b byte, score int) {
+	m.scores[a][b] = score
+	m.scores[b][a] = score
+}
+
+// DNA returns the scoring matrix of EDNAFULL restricted to the four bases:
+// 5 for a match and -4 for a mismatch, in upper and lower case.
+func DNA() *Matrix {
+	m := NewMatrix(-4, -4)
+	for _, base := range "ACGT" {
+		lower := byte(base) + 'a' - 'A'
+		m.Set(byte(base), byte(base), 5)
+		m.Set(lower, lower, 5)
+		m.Set(byte(base), lower, 5)
+	}
+	return m
+}
+
+// ParseMatrix reads a substitution matrix in the NCBI text format used to
+// distribute BLOSUM and PAM matrices: '#' comment lines, a header listing the
+// column letters and one row per letter starting with that letter. Pairs of
+// bytes missing from the matrix get its lowest score.
+func ParseMatrix(r io.Reader) (*Matrix, error) {
+	scanner := bufio.NewScanner(r)
+	var columns []string
+	var rows [][]int
+	var letters []byte
+	lowest := 0
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if columns == nil {
+			columns = fields
+			for _, column := range columns {
+				if len(column) != 1 {
+					return nil, fmt.Errorf("%w: line %d: column %q is not a single letter", ErrInvalidMatrix, line, column)
+				}
+			}
+			continue
+		}
+		if len(fields[0]) != 1 || len(fields) != len(columns)+1 {
+			return nil, fmt.Errorf("%w: line %d: expected a letter and %d scores", ErrInvalidMatrix, line, len(columns))
+		}
+		row := make([]int, len(columns))
+		for i, field := range fields[1:] {
+			score, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidMatrix, line, err)
+			}
+			row[i] = score
+			lowest = min(lowest, score)
+		}
+		letters = append(letters, fields[0][0])
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no scores", ErrInvalidMatrix)
+	}
+
+	m := NewMatrix(lowest, lowest)
+	for i, row := range rows {
+		for j, score := range row {
+			m.scores[letters[i]][columns[j][0]] = score
+		}
+	}
+	return m, nil
+}
+
+// Scoring configures an alignment. A gap of length k scores GapOpen + k*GapExtend,
+// so GapOpen = 0 gives linear gap penalties and a negative GapOpen affine ones,
+// which prefer one long gap to several short ones.
+type Scoring struct {
+	Matrix    *Matrix
+	GapOpen   int
+	GapExtend int
+}
+
+// SimpleScoring returns linear scoring with the given match and mismatch scores
+// and gap penalty per byte.
+func SimpleScoring(match, mismatch, gap int) Scoring {
+	return Scoring{Matrix: NewMatrix(match, mismatch), GapExtend: gap}
+}
+
+func (s Scoring) validate() error {
+	if s.Matrix == nil {
+		return fmt.Errorf("%w: no matrix", ErrInvalidScoring)
+	}
+	if s.GapOpen > 0 || s.GapExtend >= 0 {
+		return fmt.Errorf("%w: gaps have to lower the score", ErrInvalidScoring)
+	}
+	return nil
+}
+
+// gap returns the score of a gap of length k.
+func (s Scoring) gap(k int) int {
+	if k == 0 {
+		return 0
+	}
+	return s.GapOpen + k*s.GapExtend
+}
@@ -0,0 +1,65 @@
+package alignment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const blosumExcerpt = `#  Matrix made by matblas from blosum62.iij
+   A  R  N  W
+A  4 -1 -2 -3
+R -1  5  0 -3
+N -2  0  6 -4
+W -3 -3 -4 11
+`
+
+func TestParseMatrix(t *testing.T) {
+	m, err := ParseMatrix(strings.NewReader(blosumExcerpt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		a, b     byte
+		expected int
+	}{
+		{'A', 'A', 4},
+		{'W', 'W', 11},
+		{'R', 'N', 0},
+		{'N', 'W', -4},
+		// Missing pairs get the lowest score.
+		{'A', 'Z', -4},
+	}
+	for _, tt := range tests {
+		if score := m.Score(tt.a, tt.b); score != tt.expected {
+			t.Errorf("%c%c: expected %d, got %d", tt.a, tt.b, tt.expected, score)
+		}
+	}
+}
+
+func TestParseMatrixRejectsMalformedInput(t *testing.T) {
+	for _, input := range []string{"", "# comment only\n", "  AB\nA 1\n", "  A  R\nA 1\n", "  A\nA x\n"} {
+		if _, err := ParseMatrix(strings.NewReader(input)); !errors.Is(err, ErrInvalidMatrix) {
+			t.Errorf("%q: expected ErrInvalidMatrix, got %v", input, err)
+		}
+	}
+}
+
+func TestDNAMatrix(t *testing.T) {
+	m := DNA()
+	if m.Score('A', 'a') != 5 || m.Score('G', 'G') != 5 || m.Score('A', 'G') != -4 || m.Score('N', 'N') != -4 {
+		t.Error("unexpected DNA scores")
+	}
+}
+
+func TestScoringValidation(t *testing.T) {
+	for _, scoring := range []Scoring{
+		{GapExtend: -1},
+		{Matrix: NewMatrix(1, -1), GapExtend: 0},
+		{Matrix: NewMatrix(1, -1), GapOpen: 1, GapExtend: -1},
+	} {
+		if _, err := Global("a", "b", scoring); !errors.Is(err, ErrInvalidScoring) {
+			t.Errorf("%+v: expected ErrInvalidScoring, got %v", scoring, err)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package patternmatching
+
+// ApproximateMatch is an occurrence of the pattern with at most k errors.
+// End is the byte offset just past the occurrence and Distance the number of
+// errors. For the Hamming distance the occurrence starts at End-len(pattern);
+// for the edit distance its length may differ from the pattern's, so only the
+// end is reported, with the smallest distance of any occurrence ending there.
+type ApproximateMatch struct {
+	End      int
+	Distance int
+}
+
+// BitapHamming finds every window of the text differing from the pattern in at
+// most k characters (k-mismatch search).
+//
+// The shift-and (Bitap) algorithm keeps one bit per pattern position for every
+// number of errors d <= k: bit j of state d is set when pattern[:j+1] ends at the
+// current text position with at most d mismatches. Every text character updates
+// each state with a few word operations, no matter how long the pattern is;
+// patterns longer than 64 characters span several machine words. Every state
+// update counts as one comparison.
+func BitapHamming(text, pattern string, k int) ([]ApproximateMatch, MatchStats) {
+	var stats MatchStats
+	if pattern == "" || k < 0 {
+		return nil, stats
+	}
+	b := newBitap(pattern)
+	stats = b.tables.stats()
+	states := b.newStates(k, false)
+	old := newBitVector(b.words)
+	var matches []ApproximateMatch
+
+	for i := 0; i < len(text); i++ {
+		mask := b.masks[text[i]]
+		for d := 0; d <= k; d++ {
+			stats.Comparisons++
+			copy(old, states[d])
+			// A match extends every state by one character.
+			states[d].shiftInsertAnd(states[d], mask)
+			if d > 0 {
+				// A mismatch extends the state with one error less.
+				states[d].orShifted(b.previous)
+			}
+			copy(b.previous, old)
+		}
+		if d := b.firstMatch(states); d >= 0 {
+			matches = append(matches, ApproximateMatch{End: i + 1, Distance: d})
+		}
+	}
+	return matches, stats
+}
+
+// BitapLevenshtein finds every end position of a substring within edit distance k
+// of the pattern (k-differences search), using the Wu-Manber extension of Bitap
+// with insertions, deletions and substitutions. Every state update counts as one
+// comparison.
+func BitapLevenshtein(text, pattern string, k int) ([]ApproximateMatch, MatchStats) {
+	var stats MatchStats
+	if pattern == "" || k < 0 {
+		return nil, stats
+	}
+	b := newBitap(pattern)
+	stats = b.tables.stats()
+	states := b.newStates(k, true)
+	old := newBitVector(b.words)
+	var matches []ApproximateMatch
+
+	for i := 0; i < len(text); i++ {
+		mask := b.masks[text[i]]
+		for d := 0; d <= k; d++ {
+			stats.Comparisons++
+			copy(old, states[d])
+			states[d].shiftInsertAnd(states[d], mask)
+			if d > 0 {
+				// Substitution: pattern[j] consumed by a different text character.
+				states[d].orShifted(b.previous)
+				// Insertion: an extra text character, the pattern position stays.
+				states[d].or(b.previous)
+				// Deletion: pattern[j] skipped, the text position stays.
+				states[d].orShifted(states[d-1])
+			}
+			copy(b.previous, old)
+		}
+		if d := b.firstMatch(states); d >= 0 {
+			matches = append(matches, ApproximateMatch{End: i + 1, Distance: d})
+		}
+	}
+	return matches, stats
+}
+
+// KDifferences finds the same matches as BitapLevenshtein with dynamic programming.
+//
+// Column i of the table holds, for every pattern prefix, the smallest edit distance
+// to a substring ending at text position i. Ukkonen's cut-off only computes the band
+// of rows up to the last one with a value <= k, since the rows below cannot lead to
+// a match. This makes the search O(kn) on average instead of O(mn). Every computed
+// cell counts as one comparison.
+func KDifferences(text, pattern string, k int) ([]ApproximateMatch, MatchStats) {
+	var stats MatchStats
+	m := len(pattern)
+	if m == 0 || k < 0 {
+		return nil, stats
+	}
+
+	column := make([]int, m+1)
+	for j := range column {
+		column[j] = j
+	}
+	// lastActive is the last row whose value may be <= k.
+	lastActive := min(k+1, m)
+	var matches []ApproximateMatch
+
+	for i := 0; i < len(text); i++ {
+		diagonal, current := 0, 0
+		for j := 1; j <= lastActive; j++ {
+			stats.Comparisons++
+			if pattern[j-1] == text[i] {
+				current = diagonal
+			} else {
+				current = 1 + min(diagonal, current, column[j])
+			}
+			diagonal, column[j] = column[j], current
+		}
+
+		for lastActive > 0 && column[lastActive] > k {
+			lastActive--
+		}
+		if lastActive == m {
+			matches = append(matches, ApproximateMatch{End: i + 1, Distance: column[m]})
+		} else {
+			lastActive++
+		}
+	}
+	return matches, stats
+}
+
+// bitap holds the pattern masks shared by the Bitap searches. Bit j of masks[c]
+// is set when pattern[j] == c.
+type bitap struct {
+	m        int
+	words    int
+	masks    [256]bitVector
+	previous bitVector
+	// tables counts the masks cleared and the pattern bits set in them.
+	tables preprocessing
+}
+
+func newBitap(pattern string) *bitap {
+	words := (len(pattern) + 63) / 64
+	b := &bitap{m: len(pattern), words: words, tables: preprocessing{cost: 256 + len(pattern), memory: 256 * words * 8}}
+	for c := range b.masks {
+		b.masks[c] = newBitVector(b.words)
+	}
+	for j := 0; j < len(pattern); j++ {
+		b.masks[pattern[j]].set(j)
+	}
+	b.previous = newBitVector(b.words)
+	return b
+}
+
+// newStates returns the k+1 initial states. With deletions allowed, the first d
+// pattern characters can always be deleted at the cost of d errors.
+func (b *bitap) newStates(k int, deletions bool) []bitVector {
+	states := make([]bitVector, k+1)
+	for d := range states {
+		states[d] = newBitVector(b.words)
+		if deletions {
+			for j := 0; j < min(d, b.m); j++ {
+				states[d].set(j)
+			}
+		}
+	}
+	return states
+}
+
+// firstMatch returns the smallest number of errors whose state has the whole
+// pattern matched, or -1.
+func (b *bitap) firstMatch(states []bitVector) int {
+	for d, state := range states {
+		if state.has(b.m - 1) {
+			return d
+		}
+	}
+	return -1
+}
+
+// bitVector is a fixed size set of bits spanning as many words as needed.
+type bitVector []uint64
+
+func newBitVector(words int) bitVector {
+	return make(bitVector, words)
+}
+
+func (v bitVector) set(bit int) {
+	v[bit/64] |= 1 << (bit % 64)
+}
+
+func (v bitVector) has(bit int) bool {
+	return v[bit/64]&(1<<(bit%64)) != 0
+}
+
+// shiftInsertAnd sets v to ((src << 1) | 1) & mask. v and src may be the same vector.
+func (v bitVector) shiftInsertAnd(src, mask bitVector) {
+	carry := uint64(1)
+	for w := range v {
+		next := src[w] >> 63
+		v[w] = ((src[w] << 1) | carry) & mask[w]
+		carry = next
+	}
+}
+
+// orShifted sets v to v | (src << 1) | 1.
+func (v bitVector) orShifted(src bitVector) {
+	carry := uint64(1)
+	for w := range v {
+		v[w] |= (src[w] << 1) | carry
+		carry = src[w] >> 63
+	}
+}
+
+// or sets v to v | src.
+func (v bitVector) or(src bitVector) {
+	for w := range v {
+		v[w] |= src[w]
+	}
+}
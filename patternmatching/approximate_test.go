@@ -0,0 +1,109 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApproximateSearch(t *testing.T) {
+	text := "GATTACA GATACA GATTTACA CATTACA"
+
+	hamming, _ := BitapHamming(text, "GATTACA", 1)
+	if expected := []ApproximateMatch{{End: 7, Distance: 0}, {End: 31, Distance: 1}}; !reflect.DeepEqual(hamming, expected) {
+		t.Errorf("BitapHamming: expected %v, got %v", expected, hamming)
+	}
+
+	edits, stats := BitapLevenshtein("the quick brwn fox", "brown", 1)
+	if expected := []ApproximateMatch{{End: 14, Distance: 1}}; !reflect.DeepEqual(edits, expected) {
+		t.Errorf("BitapLevenshtein: expected %v, got %v", expected, edits)
+	}
+	if stats.Comparisons != 2*len("the quick brwn fox") {
+		t.Errorf("BitapLevenshtein: expected one comparison per character and error level, got %d", stats.Comparisons)
+	}
+}
+
+func TestApproximateSearchMatchesOracle(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		text := randomText(random, random.Intn(120), "ACGT")
+		patternLength := 1 + random.Intn(10)
+		if round%10 == 0 {
+			// Patterns longer than a machine word.
+			patternLength = 60 + random.Intn(80)
+			text = text + text + text
+		}
+		pattern := randomText(random, patternLength, "ACGT")
+		k := random.Intn(4)
+
+		if got, expected := matchesOrNil(BitapHamming(text, pattern, k)), hammingOracle(text, pattern, k); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("BitapHamming(%q, %q, %d): expected %v, got %v", text, pattern, k, expected, got)
+		}
+
+		expected := levenshteinOracle(text, pattern, k)
+		if got := matchesOrNil(BitapLevenshtein(text, pattern, k)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("BitapLevenshtein(%q, %q, %d): expected %v, got %v", text, pattern, k, expected, got)
+		}
+		if got := matchesOrNil(KDifferences(text, pattern, k)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("KDifferences(%q, %q, %d): expected %v, got %v", text, pattern, k, expected, got)
+		}
+	}
+}
+
+func TestKDifferencesCutOff(t *testing.T) {
+	text := strings.Repeat("ACGT", 250)
+	pattern := strings.Repeat("T", 40)
+	_, stats := KDifferences(text, pattern, 2)
+	if full := len(text) * len(pattern); stats.Comparisons >= full/4 {
+		t.Errorf("expected the cut-off to skip most of the %d cells, computed %d", full, stats.Comparisons)
+	}
+}
+
+func matchesOrNil(matches []ApproximateMatch, _ MatchStats) []ApproximateMatch {
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches
+}
+
+func hammingOracle(text, pattern string, k int) []ApproximateMatch {
+	var matches []ApproximateMatch
+	for start := 0; start+len(pattern) <= len(text); start++ {
+		mismatches := 0
+		for j := 0; j < len(pattern); j++ {
+			if text[start+j] != pattern[j] {
+				mismatches++
+			}
+		}
+		if mismatches <= k {
+			matches = append(matches, ApproximateMatch{End: start + len(pattern), Distance: mismatches})
+		}
+	}
+	return matches
+}
+
+// levenshteinOracle computes the full semi-global edit distance table.
+func levenshteinOracle(text, pattern string, k int) []ApproximateMatch {
+	m := len(pattern)
+	previous := make([]int, m+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	var matches []ApproximateMatch
+	for i := 0; i < len(text); i++ {
+		current := make([]int, m+1)
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if pattern[j-1] == text[i] {
+				cost = 0
+			}
+			current[j] = min(previous[j-1]+cost, previous[j]+1, current[j-1]+1)
+		}
+		if current[m] <= k {
+			matches = append(matches, ApproximateMatch{End: i + 1, Distance: current[m]})
+		}
+		previous = current
+	}
+	return matches
+}
@@ -0,0 +1,187 @@
+// Package benchmark measures the pattern matching algorithms on user supplied
+// corpora and on generated inputs, and writes the results as CSV, JSON or a
+// text summary.
+package benchmark
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"time"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// Scenario is one text and one pattern every algorithm is run on.
+type Scenario struct {
+	// Name groups the results of the scenario, e.g. "corpus.txt" or "alphabet=4".
+	Name    string
+	Text    string
+	Pattern string
+}
+
+// Result is the measurement of one algorithm on one scenario.
+type Result struct {
+	Scenario      string `json:"scenario"`
+	Pattern       string `json:"pattern"`
+	Algorithm     string `json:"algorithm"`
+	TextLength    int    `json:"text_length"`
+	PatternLength int    `json:"pattern_length"`
+	AlphabetSize  int    `json:"alphabet_size"`
+	Matches       int    `json:"matches"`
+	Comparisons   int    `json:"comparisons"`
+
+	// The rest of the statistics of the search, see patternmatching.MatchStats.
+	Shifts            int     `json:"shifts"`
+	ShiftDistance     int     `json:"shift_distance"`
+	HashComputations  int     `json:"hash_computations"`
+	SpuriousHits      int     `json:"spurious_hits"`
+	PreprocessingCost int     `json:"preprocessing_cost"`
+	TableMemory       int     `json:"table_memory"`
+	NsPerOp           float64 `json:"ns_per_op"`
+	AllocsPerOp       float64 `json:"allocs_per_op"`
+	BytesPerOp        float64 `json:"bytes_per_op"`
+}
+
+// Config controls how long every algorithm is measured.
+type Config struct {
+	// Algorithms to run. Empty means all algorithms of patternmatching.Algorithms.
+	Algorithms []patternmatching.Algorithm
+	// MinDuration is how long every algorithm runs on every scenario at least.
+	MinDuration time.Duration
+}
+
+// Run measures every algorithm on every scenario. Compiling the pattern is not
+// part of the measured time.
+func Run(scenarios []Scenario, config Config) ([]Result, error) {
+	algorithms := config.Algorithms
+	if len(algorithms) == 0 {
+		algorithms = patternmatching.Algorithms()
+	}
+
+	var results []Result
+	for _, scenario := range scenarios {
+		for _, algo := range algorithms {
+			matcher, err := patternmatching.Compile(scenario.Pattern, algo)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, measure(scenario, matcher, config.MinDuration))
+		}
+	}
+	return results, nil
+}
+
+// measure runs Count in a loop, doubling the number of iterations until the loop
+// takes at least minDuration.
+func measure(scenario Scenario, matcher patternmatching.Matcher, minDuration time.Duration) Result {
+	matches, stats := matcher.Count(scenario.Text)
+	result := Result{
+		Scenario:      scenario.Name,
+		Pattern:       scenario.Pattern,
+		Algorithm:     string(matcher.Algorithm()),
+		TextLength:    len(scenario.Text),
+		PatternLength: len(scenario.Pattern),
+		AlphabetSize:  alphabetSize(scenario.Text),
+		Matches:       matches,
+		Comparisons:   stats.Comparisons,
+
+		Shifts:            stats.Shifts,
+		ShiftDistance:     stats.ShiftDistance,
+		HashComputations:  stats.HashComputations,
+		SpuriousHits:      stats.SpuriousHits,
+		PreprocessingCost: stats.PreprocessingCost,
+		TableMemory:       stats.TableMemory,
+	}
+
+	var before, after runtime.MemStats
+	for iterations := 1; ; iterations *= 2 {
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := 0; i < iterations; i++ {
+			matcher.Count(scenario.Text)
+		}
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		if elapsed >= minDuration {
+			result.NsPerOp = float64(elapsed.Nanoseconds()) / float64(iterations)
+			result.AllocsPerOp = float64(after.Mallocs-before.Mallocs) / float64(iterations)
+			result.BytesPerOp = float64(after.TotalAlloc-before.TotalAlloc) / float64(iterations)
+			return result
+		}
+	}
+}
+
+// alphabetSize returns the number of distinct bytes of text.
+func alphabetSize(text string) int {
+	var seen [256]bool
+	size := 0
+	for i := 0; i < len(text); i++ {
+		if !seen[text[i]] {
+			seen[text[i]] = true
+			size++
+		}
+	}
+	return size
+}
+
+// sweepAlphabet is the pool the generated alphabets are taken from.
+const sweepAlphabet = "ACGTBDEFHIJKLMNOPQRSUVWXYZabcdefghijklmnopqrstuvwxyz0123456789 .,;"
+
+// AlphabetSweep generates one random text per alphabet size, each searched for a
+// pattern taken from the middle of the text, so the effect of the alphabet size
+// on the shift heuristics can be compared. Sizes are capped at the 66 characters
+// of the built-in alphabet. No scenarios are generated when either length is
+// below 1.
+func AlphabetSweep(textLength, patternLength int, sizes []int, seed int64) []Scenario {
+	if textLength < 1 || patternLength < 1 {
+		return nil
+	}
+	random := rand.New(rand.NewSource(seed))
+	var scenarios []Scenario
+	for _, size := range sizes {
+		size = max(1, min(size, len(sweepAlphabet)))
+		text := randomText(random, textLength, sweepAlphabet[:size])
+		scenarios = append(scenarios, Scenario{
+			Name:    "alphabet=" + strconv.Itoa(size),
+			Text:    text,
+			Pattern: middle(text, patternLength),
+		})
+	}
+	return scenarios
+}
+
+// PatternLengthSweep searches text for patterns of the given lengths, taken from
+// the middle of the text. Lengths below 1 are skipped.
+func PatternLengthSweep(name, text string, lengths []int) []Scenario {
+	var scenarios []Scenario
+	for _, length := range lengths {
+		pattern := middle(text, length)
+		if pattern == "" {
+			continue
+		}
+		scenarios = append(scenarios, Scenario{
+			Name:    name + "/length=" + strconv.Itoa(len(pattern)),
+			Text:    text,
+			Pattern: pattern,
+		})
+	}
+	return scenarios
+}
+
+func randomText(random *rand.Rand, n int, alphabet string) string {
+	text := make([]byte, n)
+	for i := range text {
+		text[i] = alphabet[random.Intn(len(alphabet))]
+	}
+	return string(text)
+}
+
+// middle returns length bytes from the middle of text, or all of it when it is
+// shorter, or nothing when length is negative.
+func middle(text string, length int) string {
+	length = max(0, min(length, len(text)))
+	start := (len(text) - length) / 2
+	return text[start : start+length]
+}
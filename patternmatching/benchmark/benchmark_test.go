@@ -0,0 +1,106 @@
+package benchmark
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+func TestRun(t *testing.T) {
+	scenarios := []Scenario{{Name: "quote", Text: "COMPUTER SCIENCE IS NO MORE ABOUT COMPUTERS THAN ASTRONOMY IS ABOUT TELESCOPES", Pattern: "NO"}}
+	algorithms := []patternmatching.Algorithm{patternmatching.BruteForce, patternmatching.BoyerMooreOptimized}
+
+	results, err := Run(scenarios, Config{Algorithms: algorithms})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected one result per algorithm, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Algorithm != string(algorithms[i]) || r.Matches != 2 || r.NsPerOp <= 0 || r.AlphabetSize != 17 {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+	// The comparison counts are the ones of text_pattern_matching_test.go.
+	if results[0].Comparisons != 81 || results[1].Comparisons != 45 {
+		t.Errorf("expected 81 and 45 comparisons, got %d and %d", results[0].Comparisons, results[1].Comparisons)
+	}
+	// Brute force shifts by one past every alignment and builds no tables.
+	if r := results[0]; r.Shifts != r.TextLength-1 || r.ShiftDistance != r.Shifts || r.TableMemory != 0 {
+		t.Errorf("unexpected brute force statistics %+v", r)
+	}
+	if r := results[1]; r.Shifts == 0 || r.PreprocessingCost == 0 || r.TableMemory == 0 {
+		t.Errorf("unexpected Boyer-Moore statistics %+v", r)
+	}
+}
+
+func TestSweeps(t *testing.T) {
+	scenarios := AlphabetSweep(1000, 6, []int{2, 4, 1000}, 1)
+	if len(scenarios) != 3 {
+		t.Fatalf("expected 3 scenarios, got %d", len(scenarios))
+	}
+	for i, size := range []int{2, 4, len(sweepAlphabet)} {
+		if got := alphabetSize(scenarios[i].Text); got != size || len(scenarios[i].Pattern) != 6 {
+			t.Errorf("scenario %s: expected alphabet %d and pattern length 6, got %d and %d", scenarios[i].Name, size, got, len(scenarios[i].Pattern))
+		}
+	}
+
+	lengths := PatternLengthSweep("corpus", "abcdefghij", []int{2, 4, 20})
+	var patterns []string
+	for _, scenario := range lengths {
+		patterns = append(patterns, scenario.Pattern)
+	}
+	if !reflect.DeepEqual(patterns, []string{"ef", "defg", "abcdefghij"}) {
+		t.Errorf("unexpected patterns %q", patterns)
+	}
+
+	if bad := PatternLengthSweep("corpus", "abcdefghij", []int{-3, 0}); len(bad) != 0 {
+		t.Errorf("expected no scenarios for lengths below 1, got %d", len(bad))
+	}
+	if bad := AlphabetSweep(-5, -3, []int{2}, 1); len(bad) != 0 {
+		t.Errorf("expected no scenarios for negative lengths, got %d", len(bad))
+	}
+}
+
+func TestReports(t *testing.T) {
+	results := []Result{
+		{Scenario: "s", Pattern: "p", Algorithm: "brute-force", Matches: 1, Comparisons: 30, NsPerOp: 20},
+		{Scenario: "s", Pattern: "p", Algorithm: "boyer-moore", Matches: 1, Comparisons: 10, NsPerOp: 25},
+	}
+
+	var csvOut bytes.Buffer
+	if err := WriteCSV(&csvOut, results); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], csvHeader) || records[2][7] != "10" {
+		t.Errorf("unexpected CSV %q", records)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteJSON(&jsonOut, results); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []Result
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, results) {
+		t.Errorf("JSON did not round-trip: %v, %+v", err, decoded)
+	}
+
+	var summary bytes.Buffer
+	if err := WriteSummary(&summary, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(summary.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "brute-force") || !strings.Contains(lines[1], "boyer-moore") {
+		t.Errorf("unexpected summary:\n%s", summary.String())
+	}
+}
@@ -0,0 +1,88 @@
+package benchmark
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+var csvHeader = []string{
+	"scenario", "pattern", "algorithm", "text_length", "pattern_length", "alphabet_size",
+	"matches", "comparisons", "shifts", "shift_distance", "hash_computations", "spurious_hits",
+	"preprocessing_cost", "table_memory", "ns_per_op", "allocs_per_op", "bytes_per_op",
+}
+
+// WriteCSV writes the results with a header row.
+func WriteCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		record := []string{
+			r.Scenario,
+			r.Pattern,
+			r.Algorithm,
+			strconv.Itoa(r.TextLength),
+			strconv.Itoa(r.PatternLength),
+			strconv.Itoa(r.AlphabetSize),
+			strconv.Itoa(r.Matches),
+			strconv.Itoa(r.Comparisons),
+			strconv.Itoa(r.Shifts),
+			strconv.Itoa(r.ShiftDistance),
+			strconv.Itoa(r.HashComputations),
+			strconv.Itoa(r.SpuriousHits),
+			strconv.Itoa(r.PreprocessingCost),
+			strconv.Itoa(r.TableMemory),
+			strconv.FormatFloat(r.NsPerOp, 'f', 1, 64),
+			strconv.FormatFloat(r.AllocsPerOp, 'f', 2, 64),
+			strconv.FormatFloat(r.BytesPerOp, 'f', 1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON writes the results as an indented JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// WriteSummary writes a table with one row per scenario and pattern, naming the
+// fastest algorithm and the one needing the fewest comparisons.
+func WriteSummary(w io.Writer, results []Result) error {
+	type key struct{ scenario, pattern string }
+	var order []key
+	fastest := make(map[key]Result)
+	fewest := make(map[key]Result)
+
+	for _, r := range results {
+		k := key{r.Scenario, r.Pattern}
+		best, ok := fastest[k]
+		if !ok {
+			order = append(order, k)
+		}
+		if !ok || r.NsPerOp < best.NsPerOp {
+			fastest[k] = r
+		}
+		if best, ok := fewest[k]; !ok || r.Comparisons < best.Comparisons {
+			fewest[k] = r
+		}
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SCENARIO\tPATTERN\tFASTEST\tNS/OP\tFEWEST COMPARISONS\tCOMPARISONS")
+	for _, k := range order {
+		f, c := fastest[k], fewest[k]
+		fmt.Fprintf(table, "%s\t%q\t%s\t%.1f\t%s\t%d\n", k.scenario, k.pattern, f.Algorithm, f.NsPerOp, c.Algorithm, c.Comparisons)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,208 @@
+package patternmatching
+
+// boyerMoore uses only the bad character heuristic.
+type boyerMoore struct {
+	boyerMooreSearch[byte, *byteTable]
+}
+
+// Boyer-Moore Search (only the bad character heuristic included)
+//
+// Bytes missing from the pattern map to 0 rather than -1, so a mismatch on such a
+// byte shifts by j instead of j+1. This keeps the comparison counts of the
+// original version, whose map lookup returned the zero value for them.
+func newBoyerMoore(pattern string) scanner {
+	return boyerMoore{boyerMooreSearch[byte, *byteTable]{
+		pattern:       []byte(pattern),
+		badChar:       buildBadCharTable(pattern, 0),
+		preprocessing: badCharTables(pattern),
+	}}
+}
+
+func (b boyerMoore) overlapping() scanner {
+	b.overlap = true
+	return b
+}
+
+func (b boyerMoore) scan(text string, s *search) {
+	b.scanFrom([]byte(text), 0, s)
+}
+
+// boyerMooreOptimized uses both the bad character and the good suffix heuristics.
+type boyerMooreOptimized struct {
+	boyerMooreSearch[byte, *byteTable]
+}
+
+// Boyer-Moore Search (both heuristics included).
+func newBoyerMooreOptimized(pattern string) scanner {
+	goodSuffix, cost := buildGoodSuffixTable([]byte(pattern))
+	tables := badCharTables(pattern)
+	tables.cost += cost
+	tables.memory += len(goodSuffix) * intBytes
+	return boyerMooreOptimized{boyerMooreSearch[byte, *byteTable]{
+		pattern:       []byte(pattern),
+		badChar:       buildBadCharTable(pattern, -1),
+		goodSuffix:    goodSuffix,
+		preprocessing: tables,
+	}}
+}
+
+func (b boyerMooreOptimized) scan(text string, s *search) {
+	b.scanFrom([]byte(text), 0, s)
+}
+
+// lastPositions is a bad character table: it maps a value to its last position
+// in the pattern.
+type lastPositions[T comparable] interface {
+	last(c T) int
+}
+
+// boyerMooreSearch implements both Boyer-Moore variants on strings and slices:
+// the bad character heuristic alone, or together with the good suffix heuristic
+// when goodSuffix is set.
+type boyerMooreSearch[T comparable, B lastPositions[T]] struct {
+	pattern    []T
+	badChar    B
+	goodSuffix []int
+	// overlap shifts after a match by the bad character rule applied to the value
+	// after the window, instead of past the whole match. The good suffix rule
+	// never skips a match, so the optimized variant ignores it.
+	overlap bool
+	preprocessing
+}
+
+// scanFrom aligns the pattern at position i and scans until the pattern no longer
+// fits into text. It returns the next alignment and whether the scan should go on.
+func (b boyerMooreSearch[T, B]) scanFrom(text []T, i int, s *search) (int, bool) {
+	m := len(b.pattern)
+	n := len(text)
+	optimized := b.goodSuffix != nil
+
+	for i <= n-m {
+		j := m - 1
+		if !optimized {
+			// The bad character variant counts the final comparison up front, the
+			// optimized one after the mismatch.
+			s.stats.Comparisons++
+		}
+		for j >= 0 {
+			equal := text[i+j] == b.pattern[j]
+			s.compare(i, i+j, j, equal)
+			if !equal {
+				break
+			}
+			j--
+			s.stats.Comparisons++
+		}
+
+		if j < 0 {
+			if !s.report(i) {
+				return i + m, false
+			}
+			next, reason := i+m, ShiftAfterMatch
+			switch {
+			case optimized:
+				next, reason = i+b.goodSuffix[0], ShiftGoodSuffix
+			case b.overlap && i+m < n:
+				// The value after the window has to match a pattern value in the next
+				// alignment; its last occurrence gives the smallest shift doing so.
+				next, reason = i+m-b.badChar.last(text[i+m]), ShiftBadCharacter
+			case b.overlap:
+				next, reason = i+1, ShiftBadCharacter
+			}
+			s.shift(i, next, reason)
+			i = next
+			continue
+		}
+
+		// In the Bad Character Heuristic, we want to align the last occurrence of the mismatched character
+		// in the pattern with where it appears in the text.
+		badCharShift := j - b.badChar.last(text[i+j])
+		if !optimized {
+			next := i + max(1, badCharShift)
+			s.shift(i, next, ShiftBadCharacter)
+			i = next
+			continue
+		}
+		s.stats.Comparisons++
+		goodSuffixShift := b.goodSuffix[j+1]
+		reason := ShiftBadCharacter
+		if goodSuffixShift > badCharShift {
+			reason = ShiftGoodSuffix
+		}
+		next := i + max(1, max(badCharShift, goodSuffixShift))
+		s.shift(i, next, reason)
+		i = next
+	}
+	return i, true
+}
+
+// badCharTables describes the bad character table of pattern: every entry is
+// written once, then once more for every byte of the pattern.
+func badCharTables(pattern string) preprocessing {
+	return preprocessing{cost: 256 + len(pattern), memory: 256 * intBytes}
+}
+
+// byteTable is the bad character table of a string pattern. An array indexed by
+// the byte avoids the hash lookup a map would need on every mismatch.
+type byteTable [256]int
+
+func (t *byteTable) last(c byte) int {
+	return t[c]
+}
+
+// buildBadCharTable maps every byte to its last position in the pattern, and the
+// bytes not in the pattern to absent.
+func buildBadCharTable(pattern string, absent int) *byteTable {
+	var table byteTable
+	for c := range table {
+		table[c] = absent
+	}
+	for i := 0; i < len(pattern); i++ {
+		table[pattern[i]] = i
+	}
+	return &table
+}
+
+// buildGoodSuffixTable returns the shift to apply when a mismatch happens at
+// position j-1, i.e. after pattern[j:] has matched, and the cost of building it:
+// the comparisons of the first pass and the entries of the second.
+func buildGoodSuffixTable[T comparable](pattern []T) ([]int, int) {
+	m := len(pattern)
+	shift := make([]int, m+1)
+	border := make([]int, m+1)
+	cost := m + 1
+
+	i := m
+	j := m + 1
+	border[i] = j
+
+	// First pass: preprocess border positions
+	for i > 0 {
+		for j <= m {
+			cost++
+			if pattern[i-1] == pattern[j-1] {
+				break
+			}
+			if shift[j] == 0 {
+				shift[j] = j - i
+			}
+			j = border[j]
+		}
+		i--
+		j--
+		border[i] = j
+	}
+
+	// Second pass: fill the shift table
+	j = border[0]
+	for i := 0; i <= m; i++ {
+		if shift[i] == 0 {
+			shift[i] = j
+		}
+		if i == j {
+			j = border[j]
+		}
+	}
+
+	return shift, cost
+}
@@ -0,0 +1,63 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchmarkText is a long English-like text the skipping algorithms can jump through.
+func benchmarkText() string {
+	random := rand.New(rand.NewSource(1))
+	return randomText(random, 1<<20, "abcdefghijklmnopqrstuvwxyz    ")
+}
+
+func BenchmarkSkippingAlgorithms(b *testing.B) {
+	text := benchmarkText()
+	pattern := "pattern matching"
+
+	for _, algo := range []Algorithm{BruteForce, BoyerMoore, BoyerMooreOptimized, Horspool, Sunday} {
+		b.Run(string(algo), func(b *testing.B) {
+			m := MustCompile(pattern, algo)
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				m.Count(text)
+			}
+		})
+	}
+}
+
+// BenchmarkBadCharacterLookup compares the map the bad character table used to be
+// stored in with the array it is stored in now.
+func BenchmarkBadCharacterLookup(b *testing.B) {
+	text := benchmarkText()
+	pattern := "pattern matching"
+
+	b.Run("map", func(b *testing.B) {
+		table := make(map[byte]int)
+		for i := 0; i < len(pattern); i++ {
+			table[pattern[i]] = i
+		}
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for j := 0; j < len(text); j++ {
+				sum += table[text[j]]
+			}
+			benchmarkSink = sum
+		}
+	})
+	b.Run("array", func(b *testing.B) {
+		table := buildBadCharTable(pattern, -1)
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			sum := 0
+			for j := 0; j < len(text); j++ {
+				sum += table[text[j]]
+			}
+			benchmarkSink = sum
+		}
+	})
+}
+
+// benchmarkSink keeps the compiler from removing the benchmarked loops.
+var benchmarkSink int
@@ -0,0 +1,34 @@
+package patternmatching
+
+// bruteForce checks the pattern at every position of the text.
+type bruteForce struct {
+	pattern string
+	// Brute force needs no tables.
+	preprocessing
+}
+
+func newBruteForce(pattern string) scanner {
+	return bruteForce{pattern: pattern}
+}
+
+func (b bruteForce) scan(text string, s *search) {
+	m := len(b.pattern)
+	n := len(text)
+
+	for i := 0; i <= n-m; i++ {
+		match := true
+		for j := 0; j < m; j++ {
+			s.stats.Comparisons++
+			equal := text[i+j] == b.pattern[j]
+			s.compare(i, i+j, j, equal)
+			if !equal {
+				match = false
+				break
+			}
+		}
+		if match && !s.report(i) {
+			return
+		}
+		s.shift(i, i+1, ShiftNext)
+	}
+}
@@ -0,0 +1,105 @@
+package patternmatching
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is the number of matchers a Cache keeps when created with a
+// capacity of 0 or less.
+const DefaultCacheSize = 256
+
+// CacheStats counts the lookups of a Cache.
+type CacheStats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+}
+
+// Cache keeps compiled matchers keyed by pattern, algorithm and options, so a
+// pattern searched in many texts is only preprocessed once. It holds at most its
+// capacity of matchers and evicts the least recently used one when full. A Cache is
+// safe for concurrent use, and so are the matchers it returns.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[cacheKey]*list.Element
+	// order holds the cacheEntry values, the most recently used first.
+	order *list.List
+	stats CacheStats
+}
+
+type cacheKey struct {
+	pattern string
+	algo    Algorithm
+	opts    Options
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	matcher Matcher
+}
+
+// NewCache returns an empty cache holding up to capacity matchers.
+func NewCache(capacity int) *Cache {
+	if capacity <= 0 {
+		capacity = DefaultCacheSize
+	}
+	return &Cache{capacity: capacity, entries: make(map[cacheKey]*list.Element), order: list.New()}
+}
+
+// Compile returns the cached matcher for pattern, algo and opts, compiling it on
+// the first request. Options are compared by value, so two option lists selecting
+// the same modes share a matcher. Errors are returned as by Compile and not cached.
+func (c *Cache) Compile(pattern string, algo Algorithm, opts ...Option) (Matcher, error) {
+	key := cacheKey{pattern: pattern, algo: algo}
+	for _, opt := range opts {
+		opt(&key.opts)
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return element.Value.(cacheEntry).matcher, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Compiling does not hold the lock; when two goroutines miss the same key at
+	// once, both compile it and the first stored matcher is kept.
+	m, err := Compile(pattern, algo, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(cacheEntry).matcher, nil
+	}
+	c.entries[key] = c.order.PushFront(cacheEntry{key: key, matcher: m})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(cacheEntry).key)
+		c.stats.Evictions++
+	}
+	return m, nil
+}
+
+// Len returns the number of cached matchers.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the lookup counts since the cache was created.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
@@ -0,0 +1,105 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCacheReusesCompiledMatchers(t *testing.T) {
+	cache := NewCache(0)
+	first, err := cache.Compile("abc", KnuthMorrisPratt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := cache.Compile("abc", KnuthMorrisPratt)
+	if first != second {
+		t.Error("expected the same matcher for the same key")
+	}
+	// Options are compared by value.
+	folded, _ := cache.Compile("abc", KnuthMorrisPratt, WithCaseFolding())
+	again, _ := cache.Compile("abc", KnuthMorrisPratt, WithCaseFolding(), WithCaseFolding())
+	if folded == first || folded != again {
+		t.Error("expected one matcher per set of options")
+	}
+	if other, _ := cache.Compile("abc", BoyerMoore); other == first {
+		t.Error("expected one matcher per algorithm")
+	}
+
+	expected := CacheStats{Hits: 2, Misses: 3}
+	if stats := cache.Stats(); stats != expected || cache.Len() != 3 {
+		t.Errorf("expected %+v and 3 entries, got %+v and %d", expected, stats, cache.Len())
+	}
+}
+
+func TestCacheDoesNotCacheErrors(t *testing.T) {
+	cache := NewCache(0)
+	if _, err := cache.Compile("", BruteForce); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected an empty cache, got %d entries", cache.Len())
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(2)
+	a, _ := cache.Compile("a", BruteForce)
+	cache.Compile("b", BruteForce)
+	cache.Compile("a", BruteForce)
+	cache.Compile("c", BruteForce)
+
+	if again, _ := cache.Compile("a", BruteForce); again != a {
+		t.Error("expected the recently used pattern to stay cached")
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || cache.Len() != 2 {
+		t.Errorf("expected 1 eviction and 2 entries, got %+v and %d", stats, cache.Len())
+	}
+	cache.Compile("b", BruteForce)
+	if stats := cache.Stats(); stats.Misses != 4 {
+		t.Errorf("expected the evicted pattern to be compiled again, got %+v", stats)
+	}
+}
+
+func TestMatchersAreSafeForConcurrentUse(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	texts := make([]string, 20)
+	for i := range texts {
+		texts[i] = randomText(random, 500, "ab")
+	}
+	cache := NewCache(0)
+
+	for _, algo := range Algorithms() {
+		matcher, err := cache.Compile("abab", algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := make([][]Match, len(texts))
+		for i, text := range texts {
+			expected[i], _ = matcher.FindAll(text)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 8)
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i, text := range texts {
+					shared, _ := cache.Compile("abab", algo)
+					if matches, _ := shared.FindAll(text); !reflect.DeepEqual(matches, expected[i]) {
+						errs <- errors.New(string(algo) + ": concurrent search differs from the sequential one")
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Fatal(err)
+		}
+	}
+}
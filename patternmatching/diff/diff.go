@@ -0,0 +1,193 @@
+// Package diff computes the differences between two sequences with Myers'
+// O(ND) algorithm and prints them as unified or side-by-side diffs. Texts are
+// compared as sequences of lines, words or bytes.
+package diff
+
+import (
+	"strings"
+)
+
+// Op is the kind of an edit.
+type Op int
+
+const (
+	// Equal keeps elements present in both sequences.
+	Equal Op = iota
+	// Delete removes elements of the first sequence.
+	Delete
+	// Insert adds elements of the second sequence.
+	Insert
+)
+
+func (op Op) String() string {
+	switch op {
+	case Equal:
+		return "equal"
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	}
+	return "unknown"
+}
+
+// Edit is a run of elements with the same Op: a[AStart:AEnd] and b[BStart:BEnd].
+// The range in b is empty for a Delete and the range in a for an Insert; for
+// Equal both hold the same elements.
+type Edit struct {
+	Op     Op
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+}
+
+// Diff returns the shortest edit script turning a into b, as alternating runs
+// of equal and changed elements; within a change the deletions come first. It
+// also returns the number of element comparisons, the cost measure of the
+// (matches, comparisons) functions of the pattern matching package.
+//
+// Myers' algorithm explores the edit graph by the number of changes d: for
+// every diagonal k it keeps the furthest point reachable with d changes,
+// following runs of equal elements ("snakes") for free. It stops at the first d
+// reaching the end, so it takes O((N+M)D) time for sequences of lengths N and M
+// differing in D elements, which is fast for similar inputs.
+func Diff[T comparable](a, b []T) ([]Edit, int) {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] holds v[k] for -d <= k <= d after round d, at index k+d.
+	var trace [][]int
+	comparisons := 0
+
+	for d := 0; d <= n+m; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := next(v, offset, k, d)
+			y := x - k
+			for x < n && y < m {
+				comparisons++
+				if a[x] != b[y] {
+					break
+				}
+				x++
+				y++
+			}
+			v[offset+k] = x
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		if k := n - m; k >= -d && k <= d && (k+d)%2 == 0 && v[offset+k] >= n {
+			return backtrack(trace, n, m), comparisons
+		}
+	}
+	// Unreachable: n+m changes always suffice.
+	return nil, comparisons
+}
+
+// next returns the x where round d starts on diagonal k: one step down from
+// diagonal k+1 (an insertion) or one step right from diagonal k-1 (a deletion),
+// whichever got further.
+func next(v []int, offset, k, d int) int {
+	if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+		return v[offset+k+1]
+	}
+	return v[offset+k-1] + 1
+}
+
+// backtrack walks the rounds backwards from (n, m) and collects the steps.
+func backtrack(trace [][]int, n, m int) []Edit {
+	var steps []Op
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		previous := trace[d-1]
+		at := func(k int) int { return previous[k+d-1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && at(k-1) < at(k+1) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		// The snake of round d starts after the insertion or deletion.
+		snakeX := prevX
+		if prevK == k-1 {
+			snakeX++
+		}
+		for ; x > snakeX; x, y = x-1, y-1 {
+			steps = append(steps, Equal)
+		}
+		if prevK == k+1 {
+			steps = append(steps, Insert)
+		} else {
+			steps = append(steps, Delete)
+		}
+		x, y = prevX, prevX-prevK
+	}
+	// The snake of round 0 starts at the origin.
+	for ; x > 0; x-- {
+		steps = append(steps, Equal)
+	}
+	return runs(steps)
+}
+
+// runs turns reversed single steps into runs, moving the deletions of every
+// change before its insertions.
+func runs(reversed []Op) []Edit {
+	var edits []Edit
+	x, y := 0, 0
+	for i := len(reversed) - 1; i >= 0; {
+		if reversed[i] == Equal {
+			start := i
+			for i >= 0 && reversed[i] == Equal {
+				i--
+			}
+			count := start - i
+			edits = append(edits, Edit{Op: Equal, AStart: x, AEnd: x + count, BStart: y, BEnd: y + count})
+			x, y = x+count, y+count
+			continue
+		}
+		deleted, inserted := 0, 0
+		for ; i >= 0 && reversed[i] != Equal; i-- {
+			if reversed[i] == Delete {
+				deleted++
+			} else {
+				inserted++
+			}
+		}
+		if deleted > 0 {
+			edits = append(edits, Edit{Op: Delete, AStart: x, AEnd: x + deleted, BStart: y, BEnd: y})
+		}
+		if inserted > 0 {
+			edits = append(edits, Edit{Op: Insert, AStart: x + deleted, AEnd: x + deleted, BStart: y, BEnd: y + inserted})
+		}
+		x, y = x+deleted, y+inserted
+	}
+	return edits
+}
+
+// Distance returns the number of deleted and inserted elements of an edit script.
+func Distance(script []Edit) int {
+	d := 0
+	for _, e := range script {
+		if e.Op != Equal {
+			d += e.AEnd - e.AStart + e.BEnd - e.BStart
+		}
+	}
+	return d
+}
+
+// Lines splits text into lines, each keeping its "\n". The last line has none
+// if the text does not end with a newline.
+func Lines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Words splits text into words separated by white space.
+func Words(text string) []string {
+	return strings.Fields(text)
+}
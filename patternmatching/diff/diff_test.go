@@ -0,0 +1,111 @@
+package diff
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"showmeyourcode/go/playground/patternmatching/alignment"
+)
+
+func randomString(random *rand.Rand, n int, alphabet string) string {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = alphabet[random.Intn(len(alphabet))]
+	}
+	return string(s)
+}
+
+// replay applies script to a and returns the result, failing on an
+// inconsistent script.
+func replay[T comparable](t *testing.T, a, b []T, script []Edit) []T {
+	t.Helper()
+	var out []T
+	x, y := 0, 0
+	for _, e := range script {
+		if e.AStart != x || e.BStart != y {
+			t.Fatalf("edit %+v does not continue at %d, %d", e, x, y)
+		}
+		switch e.Op {
+		case Equal:
+			if !reflect.DeepEqual(a[e.AStart:e.AEnd], b[e.BStart:e.BEnd]) {
+				t.Fatalf("equal run %+v differs", e)
+			}
+			out = append(out, a[e.AStart:e.AEnd]...)
+		case Insert:
+			out = append(out, b[e.BStart:e.BEnd]...)
+		}
+		x, y = e.AEnd, e.BEnd
+	}
+	if x != len(a) || y != len(b) {
+		t.Fatalf("script ends at %d, %d instead of %d, %d", x, y, len(a), len(b))
+	}
+	return out
+}
+
+func TestDiffIsShortest(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 300; round++ {
+		a := randomString(random, random.Intn(40), "abc")
+		b := randomString(random, random.Intn(40), "abc")
+		script, comparisons := Diff([]byte(a), []byte(b))
+
+		if out := replay(t, []byte(a), []byte(b), script); string(out) != b {
+			t.Fatalf("%q -> %q: script gives %q", a, b, out)
+		}
+		// Only the elements outside a longest common subsequence change.
+		if expected := len(a) + len(b) - 2*len(alignment.LCS(a, b)); Distance(script) != expected {
+			t.Fatalf("%q -> %q: expected %d changes, got %d", a, b, expected, Distance(script))
+		}
+		if comparisons > (len(a)+len(b)+1)*(Distance(script)+1) {
+			t.Fatalf("%q -> %q: %d comparisons exceed the O(ND) bound", a, b, comparisons)
+		}
+	}
+}
+
+func TestDiffRuns(t *testing.T) {
+	a := Lines("a\nb\nc\nd\n")
+	b := Lines("a\nx\nc\nd\ne\n")
+	script, comparisons := Diff(a, b)
+	expected := []Edit{
+		{Op: Equal, AStart: 0, AEnd: 1, BStart: 0, BEnd: 1},
+		{Op: Delete, AStart: 1, AEnd: 2, BStart: 1, BEnd: 1},
+		{Op: Insert, AStart: 2, AEnd: 2, BStart: 1, BEnd: 2},
+		{Op: Equal, AStart: 2, AEnd: 4, BStart: 2, BEnd: 4},
+		{Op: Insert, AStart: 4, AEnd: 4, BStart: 4, BEnd: 5},
+	}
+	if !reflect.DeepEqual(script, expected) {
+		t.Errorf("expected %+v, got %+v", expected, script)
+	}
+	if comparisons == 0 {
+		t.Error("expected the comparisons to be counted")
+	}
+}
+
+func TestDiffOfEqualSequencesCostsOneComparisonPerElement(t *testing.T) {
+	words := Words("the quick brown fox")
+	script, comparisons := Diff(words, words)
+	if len(script) != 1 || script[0].Op != Equal || comparisons != len(words) {
+		t.Errorf("expected one equal run after %d comparisons, got %+v after %d", len(words), script, comparisons)
+	}
+	if script, _ := Diff([]int(nil), nil); len(script) != 0 {
+		t.Errorf("expected an empty script, got %+v", script)
+	}
+}
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a\n"}},
+		{"a\n\nb", []string{"a\n", "\n", "b"}},
+	}
+	for _, tt := range tests {
+		if lines := Lines(tt.text); !reflect.DeepEqual(lines, tt.expected) {
+			t.Errorf("%q: expected %q, got %q", tt.text, tt.expected, lines)
+		}
+	}
+}
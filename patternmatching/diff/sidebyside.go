@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteSideBySide writes the lines of a and b in two columns of the given width,
+// like diff -y: the gutter between them shows '|' for a changed line, '<' for a
+// deleted and '>' for an inserted one. Only the hunks with context lines around
+// the changes are written, each after a line with its ranges; a negative context
+// writes the whole texts as a single hunk.
+func WriteSideBySide(w io.Writer, a, b []string, script []Edit, width, context int) error {
+	width = max(width, 1)
+	var hunks []Hunk
+	if context < 0 {
+		hunks = []Hunk{{AEnd: len(a), BEnd: len(b), Edits: script}}
+	} else {
+		hunks = Hunks(script, context)
+	}
+
+	bw := bufio.NewWriter(w)
+	row := func(left string, gutter byte, right string) {
+		left = column(left, width)
+		if right == "" {
+			fmt.Fprintf(bw, "%-*s %c\n", width, left, gutter)
+			return
+		}
+		fmt.Fprintf(bw, "%-*s %c %s\n", width, left, gutter, column(right, width))
+	}
+	for _, h := range hunks {
+		if context >= 0 {
+			fmt.Fprintf(bw, "@@ -%s +%s @@\n", hunkRange(h.AStart, h.AEnd), hunkRange(h.BStart, h.BEnd))
+		}
+		for i := 0; i < len(h.Edits); i++ {
+			e := h.Edits[i]
+			switch e.Op {
+			case Equal:
+				for j := 0; j < e.AEnd-e.AStart; j++ {
+					row(a[e.AStart+j], ' ', b[e.BStart+j])
+				}
+			case Insert:
+				for _, line := range b[e.BStart:e.BEnd] {
+					row("", '>', line)
+				}
+			case Delete:
+				deleted := a[e.AStart:e.AEnd]
+				var inserted []string
+				if i+1 < len(h.Edits) && h.Edits[i+1].Op == Insert {
+					i++
+					inserted = b[h.Edits[i].BStart:h.Edits[i].BEnd]
+				}
+				// A deletion followed by an insertion is a change of the lines side by side.
+				for j := 0; j < max(len(deleted), len(inserted)); j++ {
+					switch {
+					case j >= len(inserted):
+						row(deleted[j], '<', "")
+					case j >= len(deleted):
+						row("", '>', inserted[j])
+					default:
+						row(deleted[j], '|', inserted[j])
+					}
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// column returns line without its newline, cut to width bytes.
+func column(line string, width int) string {
+	line = strings.TrimSuffix(line, "\n")
+	if len(line) > width {
+		line = line[:width]
+	}
+	return line
+}
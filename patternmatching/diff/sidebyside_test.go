@@ -0,0 +1,40 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteSideBySide(t *testing.T) {
+	a := Lines("apple\nbanana\ncherry\ndate\n")
+	b := Lines("apple\nblueberry\ncherry\nelderberry\nfig\n")
+	script, _ := Diff(a, b)
+
+	var out strings.Builder
+	if err := WriteSideBySide(&out, a, b, script, 8, -1); err != nil {
+		t.Fatal(err)
+	}
+	expected := "" +
+		"apple      apple\n" +
+		"banana   | blueberr\n" +
+		"cherry     cherry\n" +
+		"date     | elderber\n" +
+		"         > fig\n"
+	if out.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out.String())
+	}
+}
+
+func TestWriteSideBySideHunks(t *testing.T) {
+	a := Lines("1\n2\n3\n4\n5\n6\n")
+	b := Lines("1\n2\n3\n4\n5\n")
+	script, _ := Diff(a, b)
+
+	var out strings.Builder
+	if err := WriteSideBySide(&out, a, b, script, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "@@ -5,2 +5 @@\n5     5\n6   <\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,225 @@
+package diff
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines diff(1) shows around changes.
+const DefaultContext = 3
+
+// noNewline marks a line without a trailing newline in a unified diff.
+const noNewline = "\\ No newline at end of file\n"
+
+// Hunk is a group of changes close enough to share their context: the edits
+// covering a[AStart:AEnd] and b[BStart:BEnd], starting and ending with at most
+// context equal elements.
+type Hunk struct {
+	AStart int
+	AEnd   int
+	BStart int
+	BEnd   int
+	Edits  []Edit
+}
+
+// Hunks groups the changes of script with context equal elements around them.
+// Changes separated by at most 2*context equal elements share a hunk.
+func Hunks(script []Edit, context int) []Hunk {
+	context = max(context, 0)
+	var hunks []Hunk
+	for i := 0; i < len(script); i++ {
+		if script[i].Op == Equal {
+			continue
+		}
+		var h Hunk
+		if i > 0 {
+			e := script[i-1]
+			keep := min(e.AEnd-e.AStart, context)
+			h.add(Edit{Op: Equal, AStart: e.AEnd - keep, AEnd: e.AEnd, BStart: e.BEnd - keep, BEnd: e.BEnd})
+		}
+		for ; i < len(script); i++ {
+			e := script[i]
+			if e.Op != Equal || i < len(script)-1 && e.AEnd-e.AStart <= 2*context {
+				h.add(e)
+				continue
+			}
+			keep := min(e.AEnd-e.AStart, context)
+			h.add(Edit{Op: Equal, AStart: e.AStart, AEnd: e.AStart + keep, BStart: e.BStart, BEnd: e.BStart + keep})
+			break
+		}
+		hunks = append(hunks, h)
+	}
+	return hunks
+}
+
+// add appends e to the hunk, leaving out empty runs of context.
+func (h *Hunk) add(e Edit) {
+	if len(h.Edits) == 0 {
+		h.AStart, h.BStart = e.AStart, e.BStart
+		h.AEnd, h.BEnd = e.AStart, e.BStart
+	}
+	if e.AEnd == e.AStart && e.BEnd == e.BStart {
+		return
+	}
+	h.Edits = append(h.Edits, e)
+	h.AEnd, h.BEnd = e.AEnd, e.BEnd
+}
+
+// WriteUnified writes the unified diff of the lines a and b, as split by Lines,
+// with context lines around every change. Nothing is written when the script has
+// no changes.
+func WriteUnified(w io.Writer, nameA, nameB string, a, b []string, script []Edit, context int) error {
+	hunks := Hunks(script, context)
+	if len(hunks) == 0 {
+		return nil
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, h := range hunks {
+		fmt.Fprintf(bw, "@@ -%s +%s @@\n", hunkRange(h.AStart, h.AEnd), hunkRange(h.BStart, h.BEnd))
+		for _, e := range h.Edits {
+			switch e.Op {
+			case Equal:
+				writeLines(bw, ' ', a[e.AStart:e.AEnd])
+			case Delete:
+				writeLines(bw, '-', a[e.AStart:e.AEnd])
+			case Insert:
+				writeLines(bw, '+', b[e.BStart:e.BEnd])
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// hunkRange formats a line range as diff(1) does: the first line counted from 1
+// and the number of lines, omitted when 1. An empty range starts at the line
+// before it.
+func hunkRange(start, end int) string {
+	switch end - start {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+func writeLines(w *bufio.Writer, prefix byte, lines []string) {
+	for _, line := range lines {
+		w.WriteByte(prefix)
+		w.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			w.WriteString("\n" + noNewline)
+		}
+	}
+}
+
+// ErrPatch is returned by Apply for a patch which is malformed or does not fit
+// the text.
+var ErrPatch = errors.New("diff: patch does not apply")
+
+// Apply applies a unified diff to text and returns the patched text. The lines
+// of every hunk have to be found at the line numbers of its header, as the
+// patch is applied without fuzz. An empty line in a hunk is an unchanged empty
+// line, as written by tools which strip trailing white space.
+func Apply(text, patch string) (string, error) {
+	lines := Lines(text)
+	var result []string
+	next := 0 // the first line of text not copied yet
+
+	patchLines := Lines(patch)
+	for i := 0; i < len(patchLines); {
+		line := patchLines[i]
+		if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			i++
+			continue
+		}
+		if !strings.HasPrefix(line, "@@ ") {
+			return "", fmt.Errorf("%w: line %d: expected a hunk header", ErrPatch, i+1)
+		}
+		start, oldCount, newCount, err := parseHunkHeader(line)
+		if err != nil {
+			return "", fmt.Errorf("%w: line %d: %v", ErrPatch, i+1, err)
+		}
+		if oldCount > 0 {
+			// Lines count from 1, except in an empty range, which names the line before it.
+			start--
+		}
+		if start < next || start > len(lines) {
+			return "", fmt.Errorf("%w: line %d: hunk out of order or past the end", ErrPatch, i+1)
+		}
+		result = append(result, lines[next:start]...)
+		next = start
+		i++
+
+		for oldCount > 0 || newCount > 0 {
+			if i == len(patchLines) || patchLines[i] == "" {
+				return "", fmt.Errorf("%w: hunk ends early", ErrPatch)
+			}
+			body := patchLines[i]
+			if body == "\n" || body == "\r\n" {
+				// Some tools drop the space of an unchanged empty line; patch accepts it.
+				body = " " + body
+			}
+			content := body[1:]
+			i++
+			if i < len(patchLines) && patchLines[i] == noNewline {
+				content = strings.TrimSuffix(content, "\n")
+				i++
+			}
+			switch body[0] {
+			case ' ', '-':
+				if next == len(lines) || lines[next] != content {
+					return "", fmt.Errorf("%w: line %d of the text differs", ErrPatch, next+1)
+				}
+				next++
+				oldCount--
+				if body[0] == ' ' {
+					result = append(result, content)
+					newCount--
+				}
+			case '+':
+				result = append(result, content)
+				newCount--
+			default:
+				return "", fmt.Errorf("%w: line %d: unexpected %q", ErrPatch, i, body[0])
+			}
+			if oldCount < 0 || newCount < 0 {
+				return "", fmt.Errorf("%w: hunk longer than its header", ErrPatch)
+			}
+		}
+	}
+	result = append(result, lines[next:]...)
+	return strings.Join(result, ""), nil
+}
+
+// parseHunkHeader parses "@@ -start,count +start,count @@" and returns the
+// start of the old range and the sizes of both.
+func parseHunkHeader(header string) (start, oldCount, newCount int, err error) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, errors.New("malformed hunk header")
+	}
+	start, oldCount, err = parseRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	_, newCount, err = parseRange(fields[2][1:])
+	return start, oldCount, newCount, err
+}
+
+func parseRange(r string) (start, count int, err error) {
+	first, size, found := strings.Cut(r, ",")
+	count = 1
+	if found {
+		if count, err = strconv.Atoi(size); err != nil {
+			return 0, 0, err
+		}
+	}
+	start, err = strconv.Atoi(first)
+	return start, count, err
+}
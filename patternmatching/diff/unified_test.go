@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func unified(a, b string, context int) string {
+	linesA, linesB := Lines(a), Lines(b)
+	script, _ := Diff(linesA, linesB)
+	var out strings.Builder
+	WriteUnified(&out, "a.txt", "b.txt", linesA, linesB, script, context)
+	return out.String()
+}
+
+func TestWriteUnified(t *testing.T) {
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	b := "one\n2\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven"
+	expected := `--- a.txt
++++ b.txt
+@@ -1,3 +1,3 @@
+ one
+-two
++2
+ three
+@@ -10 +10,2 @@
+ ten
++eleven
+\ No newline at end of file
+`
+	if out := unified(a, b, 1); out != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, out)
+	}
+	// With 3 lines of context the changes are 8 lines apart, still two hunks.
+	if out := unified(a, b, 3); strings.Count(out, "@@ -") != 2 {
+		t.Errorf("expected two hunks, got\n%s", out)
+	}
+	if out := unified(a, b, 4); strings.Count(out, "@@ -") != 1 {
+		t.Errorf("expected one hunk, got\n%s", out)
+	}
+	if out := unified(a, a, 3); out != "" {
+		t.Errorf("expected no output for equal texts, got %q", out)
+	}
+}
+
+func TestWriteUnifiedEmptyRanges(t *testing.T) {
+	if out := unified("", "new\n", 3); out != "--- a.txt\n+++ b.txt\n@@ -0,0 +1 @@\n+new\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+	if out := unified("a\nb\n", "b\n", 0); out != "--- a.txt\n+++ b.txt\n@@ -1 +0,0 @@\n-a\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestApplyRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomLines := func() string {
+		var text strings.Builder
+		for i := random.Intn(30); i > 0; i-- {
+			text.WriteString(randomString(random, random.Intn(2), "xy"))
+			text.WriteByte('\n')
+		}
+		if random.Intn(3) == 0 {
+			text.WriteString("last")
+		}
+		return text.String()
+	}
+	for round := 0; round < 300; round++ {
+		a, b := randomLines(), randomLines()
+		context := random.Intn(4)
+		patched, err := Apply(a, unified(a, b, context))
+		if err != nil || patched != b {
+			t.Fatalf("%q -> %q with context %d: got %q, %v\n%s", a, b, context, patched, err, unified(a, b, context))
+		}
+	}
+}
+
+func TestApplyEmptyContextLine(t *testing.T) {
+	// The unchanged empty line is written without its leading space.
+	patch := "--- a\n+++ b\n@@ -1,3 +1,3 @@\n a\n\n-c\n+C\n"
+	patched, err := Apply("a\n\nc\n", patch)
+	if err != nil || patched != "a\n\nC\n" {
+		t.Errorf("expected %q, got %q, %v", "a\n\nC\n", patched, err)
+	}
+}
+
+func TestApplyRejectsConflicts(t *testing.T) {
+	patch := unified("a\nb\nc\n", "a\nB\nc\n", 1)
+	tests := []string{"a\nx\nc\n", "a\n", ""}
+	for _, text := range tests {
+		if _, err := Apply(text, patch); !errors.Is(err, ErrPatch) {
+			t.Errorf("%q: expected ErrPatch, got %v", text, err)
+		}
+	}
+	for _, malformed := range []string{"garbage\n", "@@ -1 +1 @@\n", "@@ -x +1 @@\n a\n", "@@ -1 +1 @@\n?a\n"} {
+		if _, err := Apply("a\n", malformed); !errors.Is(err, ErrPatch) {
+			t.Errorf("%q: expected ErrPatch, got %v", malformed, err)
+		}
+	}
+}
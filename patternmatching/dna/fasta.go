@@ -0,0 +1,143 @@
+package dna
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidRecord is returned by Reader for input that is neither FASTA nor FASTQ.
+var ErrInvalidRecord = errors.New("dna: invalid record")
+
+// Record is one sequence of a FASTA or FASTQ file. Sequence holds the letters
+// as they appear in the file, which may include ambiguous bases; Pack them to
+// search or count k-mers. Quality is only set for FASTQ.
+type Record struct {
+	ID          string
+	Description string
+	Sequence    string
+	Quality     string
+}
+
+// Reader reads FASTA and FASTQ records. The format is detected from the first
+// character of every record: '>' starts a FASTA record, whose sequence may span
+// several lines, and '@' a FASTQ record of four lines.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+	// pending is a header line read while looking for the end of a FASTA record.
+	pending string
+	hasNext bool
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	// Sequences are often written on a single line.
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	return &Reader{scanner: scanner}
+}
+
+// Read returns the next record, or io.EOF after the last one.
+func (r *Reader) Read() (Record, error) {
+	header, err := r.nextHeader()
+	if err != nil {
+		return Record{}, err
+	}
+
+	var record Record
+	record.ID, record.Description, _ = strings.Cut(header[1:], " ")
+	if header[0] == '>' {
+		var sequence strings.Builder
+		for {
+			line, ok := r.nextLine()
+			if !ok {
+				break
+			}
+			if strings.HasPrefix(line, ">") || strings.HasPrefix(line, "@") {
+				r.pending, r.hasNext = line, true
+				break
+			}
+			sequence.WriteString(line)
+		}
+		record.Sequence = sequence.String()
+		return record, r.scanner.Err()
+	}
+
+	// The lines of a FASTQ record are read as they are: an empty read has an
+	// empty sequence and quality line.
+	sequence, ok := r.rawLine()
+	if !ok {
+		return Record{}, r.errorf("missing sequence")
+	}
+	separator, ok := r.rawLine()
+	if !ok || !strings.HasPrefix(separator, "+") {
+		return Record{}, r.errorf("missing + line")
+	}
+	quality, ok := r.rawLine()
+	if !ok || len(quality) != len(sequence) {
+		return Record{}, r.errorf("quality does not match the sequence length")
+	}
+	record.Sequence, record.Quality = sequence, quality
+	return record, nil
+}
+
+// ReadAll reads the remaining records.
+func (r *Reader) ReadAll() ([]Record, error) {
+	var records []Record
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+// nextHeader returns the header line of the next record.
+func (r *Reader) nextHeader() (string, error) {
+	if r.hasNext {
+		r.hasNext = false
+		return r.pending, nil
+	}
+	line, ok := r.nextLine()
+	if !ok {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	if line[0] != '>' && line[0] != '@' {
+		return "", r.errorf("expected a > or @ header")
+	}
+	return line, nil
+}
+
+// nextLine returns the next line which is not empty, without surrounding white
+// space. Blank lines are skipped between records and in FASTA sequences.
+func (r *Reader) nextLine() (string, bool) {
+	for {
+		line, ok := r.rawLine()
+		if !ok || line != "" {
+			return line, ok
+		}
+	}
+}
+
+// rawLine returns the next line without surrounding white space, even if empty.
+func (r *Reader) rawLine() (string, bool) {
+	if !r.scanner.Scan() {
+		return "", false
+	}
+	r.line++
+	return strings.TrimSpace(r.scanner.Text()), true
+}
+
+func (r *Reader) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidRecord, r.line, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,59 @@
+package dna
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReaderFASTAAndFASTQ(t *testing.T) {
+	input := `>chr1 first chromosome
+ACGTACGT
+ACGT
+
+>chr2
+NNAC
+@read1 lane=3
+GATTACA
++
+IIIIIII
+`
+	records, err := NewReader(strings.NewReader(input)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Record{
+		{ID: "chr1", Description: "first chromosome", Sequence: "ACGTACGTACGT"},
+		{ID: "chr2", Sequence: "NNAC"},
+		{ID: "read1", Description: "lane=3", Sequence: "GATTACA", Quality: "IIIIIII"},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %+v, got %+v", expected, records)
+	}
+}
+
+func TestReaderEmptyRead(t *testing.T) {
+	input := "@a\n\n+\n\n\n@b\nAC\n+\nII\n"
+	records, err := NewReader(strings.NewReader(input)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Record{{ID: "a"}, {ID: "b", Sequence: "AC", Quality: "II"}}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("expected %+v, got %+v", expected, records)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	for _, input := range []string{
+		"ACGT\n",
+		"@read\nACGT\n",
+		"@read\nACGT\nIIII\nIIII\n",
+		"@read\nACGT\n+\nIII\n",
+	} {
+		if _, err := NewReader(strings.NewReader(input)).ReadAll(); !errors.Is(err, ErrInvalidRecord) {
+			t.Errorf("%q: expected ErrInvalidRecord, got %v", input, err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dna
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxK is the longest k-mer CountKmers accepts; such k-mers fit in one uint64.
+const MaxK = 32
+
+// ErrInvalidK is returned for k-mer lengths outside 1..MaxK.
+var ErrInvalidK = errors.New("dna: k must be between 1 and 32")
+
+// Kmer is a k-mer packed with 2 bits per base, the first base in the highest bits.
+type Kmer uint64
+
+// String returns the k letters of the k-mer.
+func (m Kmer) String(k int) string {
+	var b strings.Builder
+	b.Grow(k)
+	for i := k - 1; i >= 0; i-- {
+		b.WriteByte(bases[m>>(2*i)&3])
+	}
+	return b.String()
+}
+
+// reverseComplement returns the k-mer of the opposite strand.
+func (m Kmer) reverseComplement(k int) Kmer {
+	var rc Kmer
+	for i := 0; i < k; i++ {
+		rc = rc<<2 | (3 - m&3)
+		m >>= 2
+	}
+	return rc
+}
+
+// CountKmers counts every substring of length k of the sequence. The k-mer of
+// each window is derived from the previous one with a shift, so counting takes
+// one map update per base.
+func CountKmers(seq Sequence, k int) (map[Kmer]int, error) {
+	return countKmers(seq, k, false)
+}
+
+// CountCanonicalKmers counts every k-mer together with its reverse complement,
+// under the smaller of the two, so the counts do not depend on which strand was
+// sequenced.
+func CountCanonicalKmers(seq Sequence, k int) (map[Kmer]int, error) {
+	return countKmers(seq, k, true)
+}
+
+func countKmers(seq Sequence, k int, canonical bool) (map[Kmer]int, error) {
+	if k < 1 || k > MaxK {
+		return nil, ErrInvalidK
+	}
+	counts := make(map[Kmer]int)
+	mask := Kmer(1)<<(2*k) - 1
+	if k == MaxK {
+		mask = ^Kmer(0)
+	}
+
+	var kmer Kmer
+	for i := 0; i < seq.Len(); i++ {
+		kmer = (kmer<<2 | Kmer(seq.code(i))) & mask
+		if i < k-1 {
+			continue
+		}
+		key := kmer
+		if canonical {
+			key = min(kmer, kmer.reverseComplement(k))
+		}
+		counts[key]++
+	}
+	return counts, nil
+}
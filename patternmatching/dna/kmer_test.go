@@ -0,0 +1,68 @@
+package dna
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestCountKmers(t *testing.T) {
+	counts, err := CountKmers(MustPack("ACGTACGA"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int{"ACG": 2, "CGT": 1, "GTA": 1, "TAC": 1, "CGA": 1}
+	if len(counts) != len(expected) {
+		t.Errorf("expected %d distinct k-mers, got %d", len(expected), len(counts))
+	}
+	for kmer, count := range counts {
+		if expected[kmer.String(3)] != count {
+			t.Errorf("%s: expected %d, got %d", kmer.String(3), expected[kmer.String(3)], count)
+		}
+	}
+}
+
+func TestCountKmersMatchesOracle(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 5, 31, 32} {
+		s := randomSequence(random, 500)
+		counts, err := CountKmers(MustPack(s), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		canonical, err := CountCanonicalKmers(MustPack(s), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string]int{}
+		expectedCanonical := map[string]int{}
+		for i := 0; i+k <= len(s); i++ {
+			kmer := s[i : i+k]
+			expected[kmer]++
+			rc, _ := ReverseComplement(kmer)
+			expectedCanonical[min(kmer, rc)]++
+		}
+		for kmer, count := range counts {
+			if expected[kmer.String(k)] != count {
+				t.Fatalf("k=%d %s: expected %d, got %d", k, kmer.String(k), expected[kmer.String(k)], count)
+			}
+		}
+		for kmer, count := range canonical {
+			if expectedCanonical[kmer.String(k)] != count {
+				t.Fatalf("k=%d canonical %s: expected %d, got %d", k, kmer.String(k), expectedCanonical[kmer.String(k)], count)
+			}
+		}
+		if len(counts) != len(expected) || len(canonical) != len(expectedCanonical) {
+			t.Fatalf("k=%d: expected %d and %d distinct k-mers, got %d and %d", k, len(expected), len(expectedCanonical), len(counts), len(canonical))
+		}
+	}
+}
+
+func TestCountKmersInvalidK(t *testing.T) {
+	for _, k := range []int{0, 33} {
+		if _, err := CountKmers(MustPack("ACGT"), k); !errors.Is(err, ErrInvalidK) {
+			t.Errorf("k=%d: expected ErrInvalidK, got %v", k, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package dna
+
+import (
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// Strand tells which strand a hit is on.
+type Strand byte
+
+const (
+	// Forward is the strand of the searched sequence.
+	Forward Strand = '+'
+	// Reverse is the opposite strand; the pattern occurs as its reverse complement
+	// on the searched sequence.
+	Reverse Strand = '-'
+)
+
+func (s Strand) String() string {
+	return string(s)
+}
+
+// Hit is an occurrence of the pattern. Start and End are offsets into the
+// searched sequence for both strands, so seq[Start:End] is the pattern on the
+// Forward strand and its reverse complement on the Reverse strand.
+type Hit struct {
+	Start  int
+	End    int
+	Strand Strand
+}
+
+// Searcher finds a pattern on both strands of sequences.
+type Searcher struct {
+	forward patternmatching.Matcher
+	// reverse searches for the reverse complement of the pattern, nil when the
+	// pattern is its own reverse complement.
+	reverse patternmatching.Matcher
+}
+
+// NewSearcher compiles pattern and its reverse complement with algo.
+//
+// A pattern equal to its reverse complement, such as the restriction site
+// GAATTC, would report every occurrence twice; it is only searched for once and
+// reported on the Forward strand.
+func NewSearcher(pattern string, algo patternmatching.Algorithm) (*Searcher, error) {
+	packed, err := Pack(pattern)
+	if err != nil {
+		return nil, err
+	}
+	forward, err := patternmatching.Compile(packed.String(), algo)
+	if err != nil {
+		return nil, err
+	}
+	searcher := &Searcher{forward: forward}
+
+	rc := packed.ReverseComplement().String()
+	if rc != packed.String() {
+		if searcher.reverse, err = patternmatching.Compile(rc, algo); err != nil {
+			return nil, err
+		}
+	}
+	return searcher, nil
+}
+
+// FindAll returns the hits on both strands ordered by start offset, Forward
+// first on ties. The statistics are summed over both searches, including the
+// tables of both matchers. The text of seq is unpacked by the first search only.
+func (s *Searcher) FindAll(seq Sequence) ([]Hit, patternmatching.MatchStats) {
+	text := seq.String()
+	forward, stats := s.forward.FindAll(text)
+	hits := make([]Hit, 0, len(forward))
+	if s.reverse == nil {
+		for _, m := range forward {
+			hits = append(hits, Hit{Start: m.Start, End: m.End, Strand: Forward})
+		}
+		return hits, stats
+	}
+
+	reverse, reverseStats := s.reverse.FindAll(text)
+	stats.AddDistinct(reverseStats)
+
+	// Merge the two sorted lists.
+	i, j := 0, 0
+	for i < len(forward) || j < len(reverse) {
+		if j == len(reverse) || i < len(forward) && forward[i].Start <= reverse[j].Start {
+			hits = append(hits, Hit{Start: forward[i].Start, End: forward[i].End, Strand: Forward})
+			i++
+		} else {
+			hits = append(hits, Hit{Start: reverse[j].Start, End: reverse[j].End, Strand: Reverse})
+			j++
+		}
+	}
+	return hits, stats
+}
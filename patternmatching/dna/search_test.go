@@ -0,0 +1,66 @@
+package dna
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+func TestSearcherFindsBothStrands(t *testing.T) {
+	// GATTACA at 2, its reverse complement TGTAATC at 12.
+	seq := MustPack("CCGATTACAGGGTGTAATCAA")
+	searcher, err := NewSearcher("gattaca", patternmatching.KnuthMorrisPratt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits, _ := searcher.FindAll(seq)
+	expected := []Hit{{Start: 2, End: 9, Strand: Forward}, {Start: 12, End: 19, Strand: Reverse}}
+	if !reflect.DeepEqual(hits, expected) {
+		t.Errorf("expected %v, got %v", expected, hits)
+	}
+}
+
+func TestSearcherReportsPalindromesOnce(t *testing.T) {
+	searcher, err := NewSearcher("GAATTC", patternmatching.BoyerMooreOptimized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits, _ := searcher.FindAll(MustPack("AGAATTCTTGAATTC"))
+	expected := []Hit{{Start: 1, End: 7, Strand: Forward}, {Start: 9, End: 15, Strand: Forward}}
+	if !reflect.DeepEqual(hits, expected) {
+		t.Errorf("expected %v, got %v", expected, hits)
+	}
+}
+
+func TestSearcherMatchesOracle(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, algo := range []patternmatching.Algorithm{patternmatching.BruteForce, patternmatching.KarpRabin64, patternmatching.TwoWay} {
+		for round := 0; round < 100; round++ {
+			s := randomSequence(random, random.Intn(300))
+			pattern := randomSequence(random, 1+random.Intn(6))
+			rc, _ := ReverseComplement(pattern)
+
+			var expected []Hit
+			for i := 0; i+len(pattern) <= len(s); i++ {
+				if s[i:i+len(pattern)] == pattern {
+					expected = append(expected, Hit{Start: i, End: i + len(pattern), Strand: Forward})
+				}
+				if rc != pattern && strings.HasPrefix(s[i:], rc) {
+					expected = append(expected, Hit{Start: i, End: i + len(pattern), Strand: Reverse})
+				}
+			}
+
+			searcher, err := NewSearcher(pattern, algo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hits, _ := searcher.FindAll(MustPack(s))
+			if len(hits) != len(expected) || len(hits) > 0 && !reflect.DeepEqual(hits, expected) {
+				t.Fatalf("%s: %q in %q: expected %v, got %v", algo, pattern, s, expected, hits)
+			}
+		}
+	}
+}
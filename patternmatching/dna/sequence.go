@@ -0,0 +1,133 @@
+// Package dna applies the pattern matching algorithms to nucleotide sequences:
+// sequences packed with 2 bits per base, FASTA and FASTQ input, search on both
+// strands and k-mer counting.
+package dna
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidBase is returned for characters other than A, C, G and T.
+var ErrInvalidBase = errors.New("dna: invalid base")
+
+// bases maps the 2-bit codes to their letters. The complement of code c is 3-c.
+const bases = "ACGT"
+
+// basesPerWord is the number of bases packed into one uint64.
+const basesPerWord = 32
+
+// Sequence is a DNA sequence packed with 2 bits per base, a quarter of the
+// memory of its text. A Sequence is immutable.
+//
+// The matchers search text, so the first search or call to String unpacks the
+// sequence and keeps the text for the following ones, shared by all copies of
+// the Sequence.
+type Sequence struct {
+	words  []uint64
+	length int
+	text   *unpacked
+}
+
+// unpacked holds the text of a Sequence once it has been unpacked.
+type unpacked struct {
+	once sync.Once
+	text string
+}
+
+// code returns the 2-bit code of base, accepting lower case letters.
+func code(base byte) (uint64, bool) {
+	switch base {
+	case 'A', 'a':
+		return 0, true
+	case 'C', 'c':
+		return 1, true
+	case 'G', 'g':
+		return 2, true
+	case 'T', 't':
+		return 3, true
+	}
+	return 0, false
+}
+
+// Pack packs a sequence of the letters A, C, G and T, in any case. Ambiguous
+// bases such as N cannot be represented with 2 bits and are rejected.
+func Pack(s string) (Sequence, error) {
+	seq := Sequence{words: make([]uint64, (len(s)+basesPerWord-1)/basesPerWord), length: len(s), text: &unpacked{}}
+	for i := 0; i < len(s); i++ {
+		c, ok := code(s[i])
+		if !ok {
+			return Sequence{}, fmt.Errorf("%w: %q at offset %d", ErrInvalidBase, s[i], i)
+		}
+		seq.words[i/basesPerWord] |= c << (2 * (i % basesPerWord))
+	}
+	return seq, nil
+}
+
+// MustPack is like Pack but panics if s is not a valid sequence.
+func MustPack(s string) Sequence {
+	seq, err := Pack(s)
+	if err != nil {
+		panic(err)
+	}
+	return seq
+}
+
+// Len returns the number of bases.
+func (s Sequence) Len() int {
+	return s.length
+}
+
+// code returns the 2-bit code of base i.
+func (s Sequence) code(i int) uint64 {
+	return s.words[i/basesPerWord] >> (2 * (i % basesPerWord)) & 3
+}
+
+// At returns base i as an upper case letter.
+func (s Sequence) At(i int) byte {
+	return bases[s.code(i)]
+}
+
+// String returns the sequence as upper case letters.
+func (s Sequence) String() string {
+	if s.text == nil {
+		// The zero Sequence is empty.
+		return s.unpack()
+	}
+	s.text.once.Do(func() {
+		s.text.text = s.unpack()
+	})
+	return s.text.text
+}
+
+func (s Sequence) unpack() string {
+	var b strings.Builder
+	b.Grow(s.length)
+	for i := 0; i < s.length; i++ {
+		b.WriteByte(s.At(i))
+	}
+	return b.String()
+}
+
+// ReverseComplement returns the sequence of the opposite strand, read in its
+// own 5' to 3' direction.
+func (s Sequence) ReverseComplement() Sequence {
+	rc := Sequence{words: make([]uint64, len(s.words)), length: s.length, text: &unpacked{}}
+	for i := 0; i < s.length; i++ {
+		j := s.length - 1 - i
+		rc.words[j/basesPerWord] |= (3 - s.code(i)) << (2 * (j % basesPerWord))
+	}
+	return rc
+}
+
+// ReverseComplement returns the reverse complement of a sequence of the letters
+// A, C, G and T.
+func ReverseComplement(s string) (string, error) {
+	seq, err := Pack(s)
+	if err != nil {
+		return "", err
+	}
+	return seq.ReverseComplement().String(), nil
+}
@@ -0,0 +1,73 @@
+package dna
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func randomSequence(random *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = bases[random.Intn(4)]
+	}
+	return string(b)
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 31, 32, 33, 100} {
+		s := randomSequence(random, n)
+		seq := MustPack(s)
+		if seq.Len() != n || seq.String() != s {
+			t.Errorf("expected %q, got %q", s, seq.String())
+		}
+		if len(seq.words) != (n+31)/32 {
+			t.Errorf("%d bases packed into %d words", n, len(seq.words))
+		}
+	}
+	if seq := MustPack("acgT"); seq.String() != "ACGT" {
+		t.Errorf("lower case: got %q", seq.String())
+	}
+}
+
+func TestStringUnpacksOnce(t *testing.T) {
+	seq := MustPack("gattaca")
+	copied := seq
+	if seq.String() != "GATTACA" {
+		t.Fatalf("unexpected text %q", seq.String())
+	}
+	if allocs := testing.AllocsPerRun(10, func() { _ = copied.String() }); allocs != 0 {
+		t.Errorf("the unpacked text was not reused: %v allocations", allocs)
+	}
+	if (Sequence{}).String() != "" {
+		t.Error("the zero Sequence should be empty")
+	}
+}
+
+func TestPackRejectsAmbiguousBases(t *testing.T) {
+	if _, err := Pack("ACGNT"); !errors.Is(err, ErrInvalidBase) {
+		t.Errorf("expected ErrInvalidBase, got %v", err)
+	}
+}
+
+func TestReverseComplement(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"A":        "T",
+		"ACGT":     "ACGT",
+		"GATTACA":  "TGTAATC",
+		"AAAACCCG": "CGGGTTTT",
+	}
+	for s, expected := range tests {
+		if rc, err := ReverseComplement(s); err != nil || rc != expected {
+			t.Errorf("%q: expected %q, got %q %v", s, expected, rc, err)
+		}
+	}
+
+	long := strings.Repeat("GATTACA", 20)
+	if twice := MustPack(long).ReverseComplement().ReverseComplement().String(); twice != long {
+		t.Errorf("reverse complement twice changed the sequence to %q", twice)
+	}
+}
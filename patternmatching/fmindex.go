@@ -0,0 +1,279 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// ErrInvalidBWT is returned by InverseBWT for input which is not the transform of
+// any text.
+var ErrInvalidBWT = errors.New("patternmatching: invalid Burrows-Wheeler transform")
+
+const (
+	// DefaultOccSampleRate is the number of BWT rows between two checkpoints of
+	// the occurrence table of an FMIndex by default.
+	DefaultOccSampleRate = 64
+	// DefaultSASampleRate is the distance between two text positions whose suffix
+	// array entry an FMIndex keeps by default.
+	DefaultSASampleRate = 32
+)
+
+// BWT returns the Burrows-Wheeler transform of text: the last column of the
+// sorted rotations of text followed by a sentinel smaller than every byte. The
+// sentinel itself is left out of bwt; primary is the row it is in. The rows are
+// read off the suffix array, as row i is the rotation starting at sa[i].
+func BWT(text string) (bwt string, primary int) {
+	sa := buildSuffixArray(text)
+	n := len(text)
+	if n == 0 {
+		return "", 0
+	}
+	last := make([]byte, 0, n)
+	// Row 0 is the rotation starting with the sentinel.
+	last = append(last, text[n-1])
+	for i, start := range sa {
+		if start == 0 {
+			primary = i + 1
+			continue
+		}
+		last = append(last, text[start-1])
+	}
+	return string(last), primary
+}
+
+// InverseBWT restores the text from its transform by following the LF mapping
+// from the sentinel row: the row of the rotation starting one character earlier
+// is the rank of the last character among its equals plus the number of smaller
+// characters.
+func InverseBWT(bwt string, primary int) (string, error) {
+	n := len(bwt)
+	if primary < 0 || primary > n || n > 0 && primary == 0 {
+		return "", ErrInvalidBWT
+	}
+	last := func(row int) byte {
+		if row > primary {
+			return bwt[row-1]
+		}
+		return bwt[row]
+	}
+
+	var counts [256]int
+	for i := 0; i < n; i++ {
+		counts[bwt[i]]++
+	}
+	var first [256]int
+	total := 1
+	for c := range first {
+		first[c] = total
+		total += counts[c]
+	}
+	// rank[row] is the number of earlier rows ending with the same character.
+	rank := make([]int, n+1)
+	var seen [256]int
+	for row := 0; row <= n; row++ {
+		if row == primary {
+			continue
+		}
+		c := last(row)
+		rank[row] = seen[c]
+		seen[c]++
+	}
+
+	text := make([]byte, n)
+	row := 0
+	for i := n - 1; i >= 0; i-- {
+		if row == primary {
+			return "", ErrInvalidBWT
+		}
+		c := last(row)
+		text[i] = c
+		row = first[c] + rank[row]
+	}
+	if row != primary {
+		return "", ErrInvalidBWT
+	}
+	return string(text), nil
+}
+
+// FMIndex counts and locates patterns in a fixed text using its Burrows-Wheeler
+// transform, in O(m) steps per pattern.
+//
+// Backward search narrows the block of rows prefixed by the pattern one
+// character at a time, from the last one, which takes the number of occurrences
+// of a character before a row. Storing it for every row would cost far more
+// than the text, so it is stored every occRate rows and the rest counted in the
+// BWT. Likewise the suffix array entry is only kept for text positions divisible
+// by saRate; locating an occurrence walks back through the text with the LF
+// mapping until it reaches one. Larger rates save memory at the cost of slower
+// counting and locating. Texts are limited to 4 GiB.
+type FMIndex struct {
+	n       int
+	last    []byte // the BWT with a 0 in the sentinel row
+	primary int
+	// code maps a byte to its index in the alphabet of the text, or -1.
+	code [256]int
+	// first holds for every alphabet index the first row starting with that byte.
+	first   []int
+	occRate int
+	// occ holds the occurrences of every alphabet byte before every occRate-th row.
+	occ []uint32
+
+	saRate int
+	// sampled marks the rows whose suffix array entry is kept in samples, and
+	// sampledRank counts the marked rows before every word of sampled.
+	sampled     []uint64
+	sampledRank []uint32
+	samples     []uint32
+}
+
+// NewFMIndex builds the FM-index of text with the given sample rates. A rate of 0
+// or less selects DefaultOccSampleRate or DefaultSASampleRate.
+func NewFMIndex(text string, occRate, saRate int) *FMIndex {
+	if occRate <= 0 {
+		occRate = DefaultOccSampleRate
+	}
+	if saRate <= 0 {
+		saRate = DefaultSASampleRate
+	}
+	n := len(text)
+	sa := append([]int{n}, buildSuffixArray(text)...)
+	bwt, primary := BWT(text)
+
+	x := &FMIndex{n: n, primary: primary, occRate: occRate, saRate: saRate}
+	x.last = make([]byte, 0, n+1)
+	x.last = append(x.last, bwt[:primary]...)
+	x.last = append(x.last, 0)
+	x.last = append(x.last, bwt[primary:]...)
+
+	var counts [256]int
+	for i := 0; i < n; i++ {
+		counts[text[i]]++
+	}
+	row := 1
+	for c := range x.code {
+		x.code[c] = -1
+		if counts[c] > 0 {
+			x.code[c] = len(x.first)
+			x.first = append(x.first, row)
+			row += counts[c]
+		}
+	}
+
+	sigma := len(x.first)
+	running := make([]uint32, sigma)
+	x.occ = make([]uint32, 0, ((n+1)/occRate+1)*sigma)
+	// Backward search ranks up to row n+1, past the last one.
+	for row := 0; row <= n+1; row++ {
+		if row%occRate == 0 {
+			x.occ = append(x.occ, running...)
+		}
+		if row <= n && row != primary {
+			running[x.code[x.last[row]]]++
+		}
+	}
+
+	words := n/64 + 1
+	x.sampled = make([]uint64, words)
+	x.sampledRank = make([]uint32, words)
+	for row, start := range sa {
+		if start%saRate == 0 {
+			x.sampled[row/64] |= 1 << (row % 64)
+			x.samples = append(x.samples, uint32(start))
+		}
+	}
+	for w := 1; w < words; w++ {
+		x.sampledRank[w] = x.sampledRank[w-1] + uint32(bits.OnesCount64(x.sampled[w-1]))
+	}
+	return x
+}
+
+// Len returns the length of the indexed text.
+func (x *FMIndex) Len() int {
+	return x.n
+}
+
+// Size returns the approximate memory used by the index in bytes.
+func (x *FMIndex) Size() int {
+	return len(x.last) + 4*len(x.occ) + 8*len(x.sampled) + 4*len(x.sampledRank) + 4*len(x.samples) + 8*len(x.first)
+}
+
+// Text restores the indexed text from the transform.
+func (x *FMIndex) Text() string {
+	text, _ := InverseBWT(string(x.last[:x.primary])+string(x.last[x.primary+1:]), x.primary)
+	return text
+}
+
+// Count returns the number of occurrences of pattern in the text. The statistics
+// count the BWT bytes read between the occurrence checkpoints.
+func (x *FMIndex) Count(pattern string) (int, MatchStats) {
+	var stats MatchStats
+	lo, hi := x.backwardSearch(pattern, &stats)
+	return hi - lo, stats
+}
+
+// Locate returns every occurrence of pattern, in order of their start offsets.
+// The statistics also count the BWT bytes read on the walks to the sampled rows.
+func (x *FMIndex) Locate(pattern string) ([]Match, MatchStats) {
+	var stats MatchStats
+	lo, hi := x.backwardSearch(pattern, &stats)
+	if lo == hi {
+		return nil, stats
+	}
+	starts := make([]int, 0, hi-lo)
+	for row := lo; row < hi; row++ {
+		starts = append(starts, x.locate(row, &stats))
+	}
+	sort.Ints(starts)
+	matches := make([]Match, len(starts))
+	for i, start := range starts {
+		matches[i] = Match{Start: start, End: start + len(pattern)}
+	}
+	return matches, stats
+}
+
+// backwardSearch returns the rows lo:hi prefixed by pattern.
+func (x *FMIndex) backwardSearch(pattern string, stats *MatchStats) (int, int) {
+	if pattern == "" {
+		return 0, 0
+	}
+	lo, hi := 0, x.n+1
+	for i := len(pattern) - 1; i >= 0 && lo < hi; i-- {
+		c := x.code[pattern[i]]
+		if c < 0 {
+			return 0, 0
+		}
+		lo = x.first[c] + x.rank(c, lo, stats)
+		hi = x.first[c] + x.rank(c, hi, stats)
+	}
+	return lo, hi
+}
+
+// rank returns the occurrences of the alphabet byte c in the rows before row.
+func (x *FMIndex) rank(c, row int, stats *MatchStats) int {
+	checkpoint := row / x.occRate
+	count := int(x.occ[checkpoint*len(x.first)+c])
+	b := x.last
+	for i := checkpoint * x.occRate; i < row; i++ {
+		stats.Comparisons++
+		if x.code[b[i]] == c && i != x.primary {
+			count++
+		}
+	}
+	return count
+}
+
+// locate returns the text position of row, stepping to the row of the previous
+// text position until reaching a sampled one.
+func (x *FMIndex) locate(row int, stats *MatchStats) int {
+	steps := 0
+	for x.sampled[row/64]&(1<<(row%64)) == 0 {
+		// Position 0 is always sampled, so row is not the sentinel row.
+		c := x.code[x.last[row]]
+		row = x.first[c] + x.rank(c, row, stats)
+		steps++
+	}
+	word := x.sampled[row/64] & (1<<(row%64) - 1)
+	sample := int(x.sampledRank[row/64]) + bits.OnesCount64(word)
+	return int(x.samples[sample]) + steps
+}
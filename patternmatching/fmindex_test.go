@@ -0,0 +1,145 @@
+package patternmatching
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBWTBanana(t *testing.T) {
+	// The sorted rotations of "banana$" end with a, n, n, b, $, a, a.
+	bwt, primary := BWT("banana")
+	if bwt != "annbaa" || primary != 4 {
+		t.Errorf("expected annbaa with the sentinel in row 4, got %s and %d", bwt, primary)
+	}
+	text, err := InverseBWT(bwt, primary)
+	if err != nil || text != "banana" {
+		t.Errorf("expected banana, got %q, %v", text, err)
+	}
+}
+
+func TestInverseBWTRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	texts := []string{"", "a", "aaaa", "\x00\x00\xff", "mississippi"}
+	for i := 0; i < 50; i++ {
+		texts = append(texts, randomText(random, random.Intn(300), "acgt\x00"))
+	}
+	for _, text := range texts {
+		bwt, primary := BWT(text)
+		restored, err := InverseBWT(bwt, primary)
+		if err != nil || restored != text {
+			t.Fatalf("%q: got %q, %v", text, restored, err)
+		}
+	}
+}
+
+func TestInverseBWTRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		bwt     string
+		primary int
+	}{
+		{"abc", 0},
+		{"abc", 4},
+		{"", 1},
+		// Following the LF mapping reaches the sentinel after one character.
+		{"ab", 1},
+	}
+	for _, tt := range tests {
+		if _, err := InverseBWT(tt.bwt, tt.primary); !errors.Is(err, ErrInvalidBWT) {
+			t.Errorf("%q, %d: expected ErrInvalidBWT, got %v", tt.bwt, tt.primary, err)
+		}
+	}
+}
+
+func TestFMIndexMatchesLinearScan(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	rates := [][2]int{{1, 1}, {3, 5}, {0, 0}, {1000, 1000}}
+	for round := 0; round < 50; round++ {
+		text := randomText(random, 1+random.Intn(300), "ab\x00")
+		for _, rate := range rates {
+			index := NewFMIndex(text, rate[0], rate[1])
+			if index.Text() != text || index.Len() != len(text) {
+				t.Fatalf("%q: the index does not restore the text", text)
+			}
+
+			for query := 0; query < 20; query++ {
+				start := random.Intn(len(text))
+				pattern := text[start:min(len(text), start+1+random.Intn(6))]
+				if query%4 == 0 {
+					pattern = randomText(random, 1+random.Intn(6), "abc")
+				}
+
+				expected, _ := MustCompile(pattern, KnuthMorrisPratt).FindAll(text)
+				matches, _ := index.Locate(pattern)
+				if !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("rates %v, %q in %q: expected %v, got %v", rate, pattern, text, expected, matches)
+				}
+				if count, _ := index.Count(pattern); count != len(expected) {
+					t.Fatalf("rates %v, %q in %q: expected count %d, got %d", rate, pattern, text, len(expected), count)
+				}
+			}
+		}
+	}
+}
+
+func TestFMIndexSampleRatesTradeMemoryForWork(t *testing.T) {
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog ", 200)
+	dense := NewFMIndex(text, 1, 1)
+	sparse := NewFMIndex(text, 128, 64)
+	if dense.Size() <= sparse.Size() {
+		t.Errorf("expected denser sampling to use more memory, got %d and %d", dense.Size(), sparse.Size())
+	}
+
+	_, denseStats := dense.Locate("fox")
+	_, sparseStats := sparse.Locate("fox")
+	if denseStats.Comparisons != 0 || sparseStats.Comparisons == 0 {
+		t.Errorf("expected work only for the sparse index, got %+v and %+v", denseStats, sparseStats)
+	}
+	if _, stats := NewFMIndex("", 0, 0).Count("a"); stats.Comparisons != 0 {
+		t.Errorf("expected no work on an empty text, got %+v", stats)
+	}
+}
+
+func BenchmarkRepeatedQueries(b *testing.B) {
+	random := rand.New(rand.NewSource(1))
+	text := randomText(random, 1<<20, "abcdefghijklmnopqrstuvwxyz ")
+	pattern := text[500000:500008]
+
+	b.Run("kmp", func(b *testing.B) {
+		m := MustCompile(pattern, KnuthMorrisPratt)
+		b.ReportMetric(float64(len(text)), "index-bytes")
+		for i := 0; i < b.N; i++ {
+			benchmarkSink, _ = m.Count(text)
+		}
+	})
+	b.Run("bm", func(b *testing.B) {
+		m := MustCompile(pattern, BoyerMooreOptimized)
+		b.ReportMetric(float64(len(text)), "index-bytes")
+		for i := 0; i < b.N; i++ {
+			benchmarkSink, _ = m.Count(text)
+		}
+	})
+	b.Run("suffix-array", func(b *testing.B) {
+		index := NewSuffixIndex(text)
+		// The text and an int per suffix for both arrays.
+		b.ResetTimer()
+		b.ReportMetric(float64(len(text)*17), "index-bytes")
+		for i := 0; i < b.N; i++ {
+			matches, _ := index.Locate(pattern)
+			benchmarkSink = len(matches)
+		}
+	})
+	for _, rate := range []int{16, 64, 256} {
+		index := NewFMIndex(text, rate, rate)
+		b.Run(fmt.Sprintf("fm-index-%d", rate), func(b *testing.B) {
+			b.ReportMetric(float64(index.Size()), "index-bytes")
+			for i := 0; i < b.N; i++ {
+				matches, _ := index.Locate(pattern)
+				benchmarkSink = len(matches)
+			}
+		})
+	}
+}
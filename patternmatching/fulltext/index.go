@@ -0,0 +1,269 @@
+// Package fulltext searches a collection of text documents. An inverted index
+// with positional postings finds the documents containing the query terms,
+// phrases are confirmed and located with the matchers of patternmatching, and
+// the results are ranked with TF-IDF or BM25.
+package fulltext
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// Posting lists the positions of a term in one document. Positions are token
+// indices, so consecutive words of the document have consecutive positions.
+type Posting struct {
+	Doc       int
+	Positions []int
+}
+
+// token is a word of a document: its lower-cased term and its byte offsets in
+// the original text.
+type token struct {
+	term       string
+	start, end int
+}
+
+type document struct {
+	name   string
+	tokens []token
+	// normalized is the terms of the document joined by single spaces, in which
+	// phrases are searched; starts holds the offset of every term in it.
+	normalized string
+	starts     []int
+}
+
+// Index is an inverted index of documents. It is safe for concurrent use: the
+// documents may be added while searches are running.
+type Index struct {
+	mu       sync.RWMutex
+	algo     patternmatching.Algorithm
+	matchers *patternmatching.Cache
+	docs     []document
+	postings map[string][]Posting
+	// tokens is the total number of tokens of all documents.
+	tokens int
+}
+
+// NewIndex returns an empty index confirming phrases with algo.
+func NewIndex(algo patternmatching.Algorithm) (*Index, error) {
+	if !slices.Contains(patternmatching.Algorithms(), algo) {
+		return nil, fmt.Errorf("fulltext: unknown algorithm %q", algo)
+	}
+	return &Index{
+		algo:     algo,
+		matchers: patternmatching.NewCache(0),
+		postings: make(map[string][]Posting),
+	}, nil
+}
+
+// IndexDir indexes every regular file under dir which holds valid UTF-8 text;
+// binary files are skipped. The documents are named by their slash-separated
+// paths relative to dir.
+func IndexDir(dir string, algo patternmatching.Algorithm) (*Index, error) {
+	index, err := NewIndex(algo)
+	if err != nil {
+		return nil, err
+	}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(content) {
+			return nil
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		index.Add(filepath.ToSlash(name), string(content))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Add indexes text under name and returns its document number.
+func (ix *Index) Add(name, text string) int {
+	doc := document{name: name, tokens: tokenize(text)}
+	var normalized strings.Builder
+	for i, t := range doc.tokens {
+		if i > 0 {
+			normalized.WriteByte(' ')
+		}
+		doc.starts = append(doc.starts, normalized.Len())
+		normalized.WriteString(t.term)
+	}
+	doc.normalized = normalized.String()
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	id := len(ix.docs)
+	ix.docs = append(ix.docs, doc)
+	ix.tokens += len(doc.tokens)
+	for position, t := range doc.tokens {
+		postings := ix.postings[t.term]
+		if n := len(postings); n > 0 && postings[n-1].Doc == id {
+			postings[n-1].Positions = append(postings[n-1].Positions, position)
+			continue
+		}
+		ix.postings[t.term] = append(postings, Posting{Doc: id, Positions: []int{position}})
+	}
+	return id
+}
+
+// Len returns the number of documents.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Name returns the name of document doc.
+func (ix *Index) Name(doc int) string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.docs[doc].name
+}
+
+// Postings returns a copy of the postings of term, ordered by document. The term
+// is normalized like the indexed text.
+func (ix *Index) Postings(term string) []Posting {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	postings := ix.postings[strings.ToLower(term)]
+	if postings == nil {
+		return nil
+	}
+	// Add appends to the positions of the last posting in place, so none of the
+	// slices is shared with the caller.
+	copied := make([]Posting, len(postings))
+	for i, posting := range postings {
+		copied[i] = Posting{Doc: posting.Doc, Positions: append([]int(nil), posting.Positions...)}
+	}
+	return copied
+}
+
+// occurrences maps a document to the spans of a term or phrase in its text.
+type occurrences map[int][]Span
+
+// termOccurrences looks up a single term.
+func (ix *Index) termOccurrences(term string) occurrences {
+	found := make(occurrences)
+	for _, posting := range ix.postings[term] {
+		tokens := ix.docs[posting.Doc].tokens
+		for _, position := range posting.Positions {
+			found[posting.Doc] = append(found[posting.Doc], Span{Start: tokens[position].start, End: tokens[position].end})
+		}
+	}
+	return found
+}
+
+// phraseOccurrences finds the documents in which the positions of the terms
+// follow each other, then searches their normalized text for the phrase with the
+// matcher of the index to locate every occurrence.
+func (ix *Index) phraseOccurrences(terms []string) (occurrences, error) {
+	if len(terms) == 1 {
+		return ix.termOccurrences(terms[0]), nil
+	}
+	matcher, err := ix.matchers.Compile(strings.Join(terms, " "), ix.algo,
+		patternmatching.WithOverlap(patternmatching.Overlapping))
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(occurrences)
+	for _, doc := range ix.phraseCandidates(terms) {
+		d := ix.docs[doc]
+		matches, _ := matcher.FindAll(d.normalized)
+		for _, match := range matches {
+			// The match has to start and end on whole terms.
+			first := sort.SearchInts(d.starts, match.Start)
+			last := first + len(terms) - 1
+			if first == len(d.starts) || d.starts[first] != match.Start || last >= len(d.tokens) ||
+				d.starts[last]+len(d.tokens[last].term) != match.End {
+				continue
+			}
+			found[doc] = append(found[doc], Span{Start: d.tokens[first].start, End: d.tokens[last].end})
+		}
+	}
+	return found, nil
+}
+
+// phraseCandidates returns the documents containing the terms at consecutive
+// positions, according to the postings.
+func (ix *Index) phraseCandidates(terms []string) []int {
+	lists := make([]map[int][]int, len(terms))
+	for i, term := range terms {
+		lists[i] = make(map[int][]int)
+		for _, posting := range ix.postings[term] {
+			lists[i][posting.Doc] = posting.Positions
+		}
+	}
+
+	var candidates []int
+	for _, posting := range ix.postings[terms[0]] {
+		for _, position := range posting.Positions {
+			if followedBy(lists[1:], posting.Doc, position) {
+				candidates = append(candidates, posting.Doc)
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+// followedBy tells whether the i-th of lists has a position position+1+i in doc.
+func followedBy(lists []map[int][]int, doc, position int) bool {
+	for i, list := range lists {
+		positions := list[doc]
+		if _, ok := slices.BinarySearch(positions, position+1+i); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits text into runs of letters and digits, lower-cased.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case word && start < 0:
+			start = i
+		case !word && start >= 0:
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// terms returns the normalized terms of text.
+func terms(text string) []string {
+	var list []string
+	for _, t := range tokenize(text) {
+		list = append(list, t.term)
+	}
+	return list
+}
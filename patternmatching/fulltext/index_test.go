@@ -0,0 +1,165 @@
+package fulltext
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+func newTestIndex(t *testing.T, docs ...string) *Index {
+	t.Helper()
+	index, err := NewIndex(patternmatching.KnuthMorrisPratt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, text := range docs {
+		index.Add(string(rune('a'+i)), text)
+	}
+	return index
+}
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize("Go, Gophers! Ünïcode 42x")
+	expected := []token{{"go", 0, 2}, {"gophers", 4, 11}, {"ünïcode", 13, 22}, {"42x", 23, 26}}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("expected %v, got %v", expected, tokens)
+	}
+}
+
+func TestPositionalPostings(t *testing.T) {
+	index := newTestIndex(t, "the cat and the hat", "a hat")
+	expected := []Posting{{Doc: 0, Positions: []int{4}}, {Doc: 1, Positions: []int{1}}}
+	if postings := index.Postings("HAT"); !reflect.DeepEqual(postings, expected) {
+		t.Errorf("expected %v, got %v", expected, postings)
+	}
+	if postings := index.Postings("the"); len(postings) != 1 || !reflect.DeepEqual(postings[0].Positions, []int{0, 3}) {
+		t.Errorf("expected positions 0 and 3, got %v", postings)
+	}
+}
+
+func TestPostingsAreCopied(t *testing.T) {
+	index := newTestIndex(t, "the cat", "the hat")
+	postings := index.Postings("the")
+	postings[0].Positions[0] = 7
+	postings[1] = Posting{}
+	expected := []Posting{{Doc: 0, Positions: []int{0}}, {Doc: 1, Positions: []int{0}}}
+	if got := index.Postings("the"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("changing the result changed the index: %v", got)
+	}
+
+	// Adding a document while the postings are read must not race.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			index.Add("more", "the the the")
+		}
+	}()
+	sum := 0
+	for i := 0; i < 100; i++ {
+		for _, posting := range index.Postings("the") {
+			for _, position := range posting.Positions {
+				sum += position
+			}
+		}
+	}
+	<-done
+}
+
+func TestPhraseOccurrencesMatchOracle(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"to", "be", "or", "not"}
+	for _, algo := range []patternmatching.Algorithm{patternmatching.KnuthMorrisPratt, patternmatching.BoyerMoore, patternmatching.TwoWay} {
+		index, err := NewIndex(algo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var docs [][]string
+		for d := 0; d < 20; d++ {
+			doc := make([]string, random.Intn(30))
+			for i := range doc {
+				doc[i] = words[random.Intn(len(words))]
+			}
+			docs = append(docs, doc)
+			index.Add("doc", strings.Join(doc, " "))
+		}
+
+		for round := 0; round < 50; round++ {
+			phrase := make([]string, 1+random.Intn(3))
+			for i := range phrase {
+				phrase[i] = words[random.Intn(len(words))]
+			}
+			found, err := index.phraseOccurrences(phrase)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for d, doc := range docs {
+				count := 0
+				for i := 0; i+len(phrase) <= len(doc); i++ {
+					if reflect.DeepEqual(doc[i:i+len(phrase)], phrase) {
+						count++
+					}
+				}
+				if len(found[d]) != count {
+					t.Fatalf("%s: %q in %q: expected %d occurrences, got %v", algo, phrase, doc, count, found[d])
+				}
+			}
+		}
+	}
+}
+
+func TestPhraseMatchesWholeTerms(t *testing.T) {
+	index := newTestIndex(t, "Scat, the Cat. Hat!")
+	found, err := index.phraseOccurrences([]string{"cat", "hat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []Span{{Start: 10, End: 18}}; !reflect.DeepEqual(found[0], expected) {
+		t.Errorf("expected %v, got %v", expected, found[0])
+	}
+	if found, _ := index.phraseOccurrences([]string{"at", "the"}); len(found) != 0 {
+		t.Errorf("expected no match inside terms, got %v", found)
+	}
+}
+
+func TestIndexDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":        "pattern matching",
+		"sub/b.md":     "inverted index",
+		"binary.bin":   "\xff\xfe\x00",
+		"sub/deep/c.t": "index of patterns",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	index, err := IndexDir(dir, patternmatching.BoyerMooreOptimized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for doc := 0; doc < index.Len(); doc++ {
+		names = append(names, index.Name(doc))
+	}
+	if expected := []string{"a.txt", "sub/b.md", "sub/deep/c.t"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestNewIndexRejectsUnknownAlgorithm(t *testing.T) {
+	if _, err := NewIndex("grep"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,295 @@
+package fulltext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ErrQuery is returned for queries which cannot be parsed.
+var ErrQuery = errors.New("fulltext: invalid query")
+
+// A query is a boolean expression of terms and quoted phrases:
+//
+//	go AND (channel OR "wait group") NOT mutex
+//
+// Words next to each other are joined with AND. NOT, and a leading '-' on a
+// word or phrase, exclude documents. The operators have to be written in upper
+// case; "and" is a term.
+type node interface {
+	// eval returns the documents matching the node.
+	eval(ix *Index) (docSet, error)
+}
+
+type termNode struct {
+	// terms holds one term, or several for a phrase.
+	terms []string
+	// found is set by eval and used for ranking and highlighting.
+	found occurrences
+}
+
+type andNode struct{ left, right node }
+
+type orNode struct{ left, right node }
+
+type notNode struct{ operand node }
+
+// docSet is a set of document numbers.
+type docSet map[int]bool
+
+func (n *termNode) eval(ix *Index) (docSet, error) {
+	found, err := ix.phraseOccurrences(n.terms)
+	if err != nil {
+		return nil, err
+	}
+	n.found = found
+	docs := make(docSet, len(found))
+	for doc := range found {
+		docs[doc] = true
+	}
+	return docs, nil
+}
+
+func (n andNode) eval(ix *Index) (docSet, error) {
+	left, right, err := evalBoth(ix, n.left, n.right)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(docSet)
+	for doc := range left {
+		if right[doc] {
+			docs[doc] = true
+		}
+	}
+	return docs, nil
+}
+
+func (n orNode) eval(ix *Index) (docSet, error) {
+	left, right, err := evalBoth(ix, n.left, n.right)
+	if err != nil {
+		return nil, err
+	}
+	for doc := range right {
+		left[doc] = true
+	}
+	return left, nil
+}
+
+func (n notNode) eval(ix *Index) (docSet, error) {
+	excluded, err := n.operand.eval(ix)
+	if err != nil {
+		return nil, err
+	}
+	docs := make(docSet)
+	for doc := range ix.docs {
+		if !excluded[doc] {
+			docs[doc] = true
+		}
+	}
+	return docs, nil
+}
+
+func evalBoth(ix *Index, left, right node) (docSet, docSet, error) {
+	l, err := left.eval(ix)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := right.eval(ix)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+// positive returns the terms and phrases of the query which are not negated;
+// they are the ones ranking and highlighting the results.
+func positive(n node) []*termNode {
+	switch n := n.(type) {
+	case *termNode:
+		return []*termNode{n}
+	case andNode:
+		return append(positive(n.left), positive(n.right)...)
+	case orNode:
+		return append(positive(n.left), positive(n.right)...)
+	}
+	return nil
+}
+
+// item is a lexical element of a query.
+type item struct {
+	kind itemKind
+	// terms holds the normalized terms of a word or phrase.
+	terms []string
+}
+
+type itemKind int
+
+const (
+	itemTerms itemKind = iota
+	itemAnd
+	itemOr
+	itemNot
+	itemOpen
+	itemClose
+)
+
+func lex(query string) ([]item, error) {
+	var items []item
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			items = append(items, item{kind: itemOpen})
+			i++
+		case c == ')':
+			items = append(items, item{kind: itemClose})
+			i++
+		case c == '-' && i+1 < len(query) && query[i+1] != ' ':
+			items = append(items, item{kind: itemNot})
+			i++
+		case c == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated phrase", ErrQuery)
+			}
+			phrase := terms(query[i+1 : i+1+end])
+			if len(phrase) == 0 {
+				return nil, fmt.Errorf("%w: empty phrase", ErrQuery)
+			}
+			items = append(items, item{kind: itemTerms, terms: phrase})
+			i += end + 2
+		default:
+			end := strings.IndexFunc(query[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"'
+			})
+			if end < 0 {
+				end = len(query) - i
+			}
+			word := query[i : i+end]
+			i += end
+			switch word {
+			case "AND":
+				items = append(items, item{kind: itemAnd})
+			case "OR":
+				items = append(items, item{kind: itemOr})
+			case "NOT":
+				items = append(items, item{kind: itemNot})
+			default:
+				// Words such as "don't" hold several terms and are searched as a phrase;
+				// words of punctuation only hold none and are ignored.
+				if t := terms(word); len(t) > 0 {
+					items = append(items, item{kind: itemTerms, terms: t})
+				}
+			}
+		}
+	}
+	return items, nil
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or    = and { "OR" and }
+//	and   = unary { [ "AND" ] unary }
+//	unary = "NOT" unary | primary
+//	primary = terms | "(" or ")"
+type parser struct {
+	items []item
+	pos   int
+}
+
+func parseQuery(query string) (node, error) {
+	items, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no terms", ErrQuery)
+	}
+	p := &parser{items: items}
+	n, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.items) {
+		return nil, fmt.Errorf("%w: unexpected )", ErrQuery)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() (itemKind, bool) {
+	if p.pos == len(p.items) {
+		return 0, false
+	}
+	return p.items[p.pos].kind, true
+}
+
+func (p *parser) or() (node, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for kind, ok := p.peek(); ok && kind == itemOr; kind, ok = p.peek() {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		kind, ok := p.peek()
+		if !ok || kind == itemOr || kind == itemClose {
+			return left, nil
+		}
+		if kind == itemAnd {
+			p.pos++
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	kind, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("%w: missing operand", ErrQuery)
+	}
+	switch kind {
+	case itemNot:
+		p.pos++
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	case itemOpen:
+		p.pos++
+		n, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if kind, ok := p.peek(); !ok || kind != itemClose {
+			return nil, fmt.Errorf("%w: missing )", ErrQuery)
+		}
+		p.pos++
+		return n, nil
+	case itemTerms:
+		p.pos++
+		return &termNode{terms: p.items[p.pos-1].terms}, nil
+	}
+	return nil, fmt.Errorf("%w: missing operand", ErrQuery)
+}
@@ -0,0 +1,69 @@
+package fulltext
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestBooleanQueries(t *testing.T) {
+	index := newTestIndex(t,
+		"go channels and goroutines",        // a
+		"a wait group waits for goroutines", // b
+		"mutex and wait group",              // c
+		"rust ownership",                    // d
+	)
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"goroutines", []string{"a", "b"}},
+		{"GOROUTINES wait", []string{"b"}},
+		{"goroutines AND wait", []string{"b"}},
+		{"go OR rust", []string{"a", "d"}},
+		{`"wait group"`, []string{"b", "c"}},
+		{`"group wait"`, nil},
+		{`"wait group" NOT mutex`, []string{"b"}},
+		{`"wait group" -mutex`, []string{"b"}},
+		{"NOT goroutines", []string{"c", "d"}},
+		{`(go OR mutex) AND -"wait group"`, []string{"a"}},
+		{"rust OR go AND channels", []string{"a", "d"}},
+		{"and", []string{"a", "c"}},
+		{"missing", nil},
+	}
+	for _, tt := range tests {
+		results, err := index.Search(tt.query, BM25)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.query, err)
+		}
+		var documents []string
+		for _, result := range results {
+			documents = append(documents, result.Document)
+		}
+		sort.Strings(documents)
+		if !reflect.DeepEqual(documents, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.query, tt.expected, documents)
+		}
+	}
+}
+
+func TestInvalidQueries(t *testing.T) {
+	index := newTestIndex(t, "text")
+	for _, query := range []string{"", "  ", `"open`, `""`, "(go", "go)", "go AND", "NOT", "go OR OR rust"} {
+		if _, err := index.Search(query, BM25); !errors.Is(err, ErrQuery) {
+			t.Errorf("%q: expected ErrQuery, got %v", query, err)
+		}
+	}
+}
+
+func TestWordsWithPunctuationArePhrases(t *testing.T) {
+	index := newTestIndex(t, "don't panic", "don t", "t don")
+	results, err := index.Search("don't", TFIDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Document != "a" || results[1].Document != "b" {
+		t.Errorf("expected a and b, got %v", results)
+	}
+}
@@ -0,0 +1,102 @@
+package fulltext
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrUnknownRanking is returned for rankings other than TFIDF and BM25.
+var ErrUnknownRanking = errors.New("fulltext: unknown ranking")
+
+// Ranking selects how the results of a search are scored.
+type Ranking string
+
+const (
+	// TFIDF scores a document by the sum over the query terms of
+	// (1 + ln tf) * ln(1 + N/df), where tf is the number of occurrences of the
+	// term in the document, df the number of documents containing it and N the
+	// number of documents.
+	TFIDF Ranking = "tf-idf"
+	// BM25 is Okapi BM25 with k1 = 1.2 and b = 0.75. Unlike TFIDF, the weight of
+	// repeated occurrences saturates and long documents are penalised.
+	BM25 Ranking = "bm25"
+)
+
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Span is the byte offsets of an occurrence of a query term or phrase in a
+// document.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Result is a document matching a query. Matches holds the occurrences of the
+// terms and phrases which are not negated, in order.
+type Result struct {
+	Document string  `json:"document"`
+	Score    float64 `json:"score"`
+	Matches  []Span  `json:"matches,omitempty"`
+}
+
+// Search returns the documents matching query, the best scoring first and
+// documents scoring the same in the order they were added. A phrase counts as a
+// single term for scoring.
+func (ix *Index) Search(query string, ranking Ranking) ([]Result, error) {
+	if ranking != TFIDF && ranking != BM25 {
+		return nil, ErrUnknownRanking
+	}
+	root, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	docs, err := root.eval(ix)
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := positive(root)
+	results := make([]Result, 0, len(docs))
+	ids := make([]int, 0, len(docs))
+	for doc := range docs {
+		ids = append(ids, doc)
+	}
+	sort.Ints(ids)
+	for _, doc := range ids {
+		result := Result{Document: ix.docs[doc].name}
+		for _, leaf := range leaves {
+			spans := leaf.found[doc]
+			if len(spans) == 0 {
+				continue
+			}
+			result.Score += ix.score(ranking, doc, len(spans), len(leaf.found))
+			result.Matches = append(result.Matches, spans...)
+		}
+		sort.Slice(result.Matches, func(i, j int) bool {
+			return result.Matches[i].Start < result.Matches[j].Start
+		})
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
+// score weights a term occurring tf times in doc and in df documents.
+func (ix *Index) score(ranking Ranking, doc, tf, df int) float64 {
+	n := float64(len(ix.docs))
+	if ranking == TFIDF {
+		return (1 + math.Log(float64(tf))) * math.Log(1+n/float64(df))
+	}
+	idf := math.Log(1 + (n-float64(df)+0.5)/(float64(df)+0.5))
+	length := float64(len(ix.docs[doc].tokens))
+	average := float64(ix.tokens) / n
+	f := float64(tf)
+	return idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*length/average))
+}
@@ -0,0 +1,68 @@
+package fulltext
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRankingOrdersResults(t *testing.T) {
+	index := newTestIndex(t,
+		"search engines rank documents",
+		"search search search",
+		"a very long document which mentions search once among many other words",
+	)
+	for _, ranking := range []Ranking{TFIDF, BM25} {
+		results, err := index.Search("search", ranking)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var order []string
+		for _, result := range results {
+			order = append(order, result.Document)
+		}
+		if expected := []string{"b", "a", "c"}; !reflect.DeepEqual(order, expected) {
+			t.Errorf("%s: expected %v, got %v", ranking, expected, order)
+		}
+	}
+}
+
+func TestScores(t *testing.T) {
+	index := newTestIndex(t, "go go", "go rust", "rust")
+	results, err := index.Search("go", TFIDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idf := math.Log(1 + 3.0/2)
+	if expected := (1 + math.Log(2)) * idf; math.Abs(results[0].Score-expected) > 1e-9 {
+		t.Errorf("expected a tf-idf score of %f, got %f", expected, results[0].Score)
+	}
+
+	results, _ = index.Search("go", BM25)
+	// Both documents have the average length of 5/3 tokens.
+	bm25IDF := math.Log(1 + (3-2+0.5)/(2+0.5))
+	norm := 1.2 * (1 - 0.75 + 0.75*2/(5.0/3))
+	if expected := bm25IDF * 2 * 2.2 / (2 + norm); math.Abs(results[0].Score-expected) > 1e-9 {
+		t.Errorf("expected a BM25 score of %f, got %f", expected, results[0].Score)
+	}
+}
+
+func TestResultsHighlightPositiveTerms(t *testing.T) {
+	index := newTestIndex(t, "Go is fun, and the Go gopher is cute")
+	results, err := index.Search(`"go gopher" OR fun -rust`, BM25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Span{{Start: 6, End: 9}, {Start: 19, End: 28}}
+	if len(results) != 1 || !reflect.DeepEqual(results[0].Matches, expected) {
+		t.Errorf("expected %v, got %v", expected, results)
+	}
+}
+
+func TestUnknownRanking(t *testing.T) {
+	index := newTestIndex(t, "text")
+	if _, err := index.Search("text", "pagerank"); !errors.Is(err, ErrUnknownRanking) {
+		t.Errorf("expected ErrUnknownRanking, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package fulltext
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// DefaultLimit is the number of results the search endpoint returns by default.
+const DefaultLimit = 10
+
+// searchResponse is the body of a successful search.
+type searchResponse struct {
+	Query   string   `json:"query"`
+	Ranking Ranking  `json:"ranking"`
+	Total   int      `json:"total"`
+	Results []Result `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler serves the index over HTTP:
+//
+//	GET /search?q=go+AND+"wait+group"&ranking=bm25&limit=10
+//
+// ranking is tf-idf or bm25, the default, and limit defaults to DefaultLimit. The
+// response is a JSON object with the query, the ranking, the total number of
+// matching documents and the best results. Invalid requests get status 400 and a
+// JSON object with an error message.
+func NewHandler(ix *Index) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		ranking := Ranking(query.Get("ranking"))
+		if ranking == "" {
+			ranking = BM25
+		}
+		limit := DefaultLimit
+		if value := query.Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit < 0 {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Error: "limit must be a non-negative integer"})
+				return
+			}
+		}
+
+		results, err := ix.Search(query.Get("q"), ranking)
+		if errors.Is(err, ErrQuery) || errors.Is(err, ErrUnknownRanking) {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+			return
+		}
+		response := searchResponse{Query: query.Get("q"), Ranking: ranking, Total: len(results), Results: results}
+		if len(results) > limit {
+			response.Results = results[:limit]
+		}
+		writeJSON(w, http.StatusOK, response)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
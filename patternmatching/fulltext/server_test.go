@@ -0,0 +1,59 @@
+package fulltext
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSearchEndpoint(t *testing.T) {
+	index := newTestIndex(t, "wait group", "wait for the group", "mutex")
+	server := httptest.NewServer(NewHandler(index))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/search?" + url.Values{"q": {"wait group"}, "limit": {"1"}}.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK || response.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected response %s %s", response.Status, response.Header.Get("Content-Type"))
+	}
+
+	var body searchResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Total != 2 || body.Ranking != BM25 || len(body.Results) != 1 || body.Results[0].Document != "a" {
+		t.Errorf("unexpected body %+v", body)
+	}
+}
+
+func TestSearchEndpointErrors(t *testing.T) {
+	handler := NewHandler(newTestIndex(t, "text"))
+	tests := []struct {
+		method, target string
+		status         int
+	}{
+		{http.MethodGet, "/search?q=%22open", http.StatusBadRequest},
+		{http.MethodGet, "/search?q=text&ranking=pagerank", http.StatusBadRequest},
+		{http.MethodGet, "/search?q=text&limit=-1", http.StatusBadRequest},
+		{http.MethodPost, "/search?q=text", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(tt.method, tt.target, nil))
+		if recorder.Code != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.target, tt.status, recorder.Code)
+		}
+		if tt.status == http.StatusBadRequest {
+			var body errorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || body.Error == "" {
+				t.Errorf("%s: expected a JSON error, got %q", tt.target, recorder.Body.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package patternmatching
+
+// horspool is the Boyer-Moore-Horspool algorithm. It drops the good suffix rule
+// and always shifts by the bad character rule applied to the last byte of the
+// window, whatever position the mismatch happened at.
+type horspool struct {
+	pattern string
+	// shift[c] is the distance from the last occurrence of c in pattern[:m-1] to
+	// the end of the pattern, or m when c does not occur there.
+	shift *[256]int
+	preprocessing
+}
+
+// Boyer-Moore-Horspool Search
+func newHorspool(pattern string) scanner {
+	m := len(pattern)
+	var shift [256]int
+	for c := range shift {
+		shift[c] = m
+	}
+	for i := 0; i < m-1; i++ {
+		shift[pattern[i]] = m - 1 - i
+	}
+	return horspool{pattern: pattern, shift: &shift, preprocessing: preprocessing{cost: 256 + max(m-1, 0), memory: 256 * intBytes}}
+}
+
+func (h horspool) scan(text string, s *search) {
+	m := len(h.pattern)
+	n := len(text)
+
+	for i := 0; i <= n-m; {
+		j := m - 1
+		for j >= 0 {
+			s.stats.Comparisons++
+			equal := text[i+j] == h.pattern[j]
+			s.compare(i, i+j, j, equal)
+			if !equal {
+				break
+			}
+			j--
+		}
+		if j < 0 && !s.report(i) {
+			return
+		}
+		next := i + h.shift[text[i+m-1]]
+		s.shift(i, next, ShiftLastCharacter)
+		i = next
+	}
+}
+
+// sunday is the Sunday (quick search) algorithm. It shifts by the bad character
+// rule applied to the byte just after the window, which is always part of the
+// next alignment, so the shift can reach m+1.
+type sunday struct {
+	pattern string
+	// shift[c] is the distance from the last occurrence of c in the pattern to the
+	// byte after the end of the pattern, or m+1 when c does not occur.
+	shift *[256]int
+	preprocessing
+}
+
+// Sunday (Quick Search)
+func newSunday(pattern string) scanner {
+	m := len(pattern)
+	var shift [256]int
+	for c := range shift {
+		shift[c] = m + 1
+	}
+	for i := 0; i < m; i++ {
+		shift[pattern[i]] = m - i
+	}
+	return sunday{pattern: pattern, shift: &shift, preprocessing: badCharTables(pattern)}
+}
+
+// scan compares the window from left to right; the order does not matter for
+// the shift.
+func (q sunday) scan(text string, s *search) {
+	m := len(q.pattern)
+	n := len(text)
+
+	for i := 0; i <= n-m; {
+		j := 0
+		for j < m {
+			s.stats.Comparisons++
+			equal := text[i+j] == q.pattern[j]
+			s.compare(i, i+j, j, equal)
+			if !equal {
+				break
+			}
+			j++
+		}
+		if j == m && !s.report(i) {
+			return
+		}
+		if i+m == n {
+			return
+		}
+		next := i + q.shift[text[i+m]]
+		s.shift(i, next, ShiftNextCharacter)
+		i = next
+	}
+}
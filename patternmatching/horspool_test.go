@@ -0,0 +1,46 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestHorspoolAndSundayMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, algo := range []Algorithm{Horspool, Sunday} {
+		for _, alphabet := range []string{"a", "ab", "ACGT", "abcdefghijklmnopqrstuvwxyz"} {
+			for round := 0; round < 200; round++ {
+				text := randomText(random, random.Intn(500), alphabet)
+				pattern := randomText(random, 1+random.Intn(12), alphabet)
+
+				expected, _ := MustCompile(pattern, BruteForce).FindAll(text)
+				matches, _ := MustCompile(pattern, algo).FindAll(text)
+				if !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("%s: %q in %q: expected %v, got %v", algo, pattern, text, expected, matches)
+				}
+			}
+		}
+	}
+}
+
+func TestHorspoolAndSundayShiftTables(t *testing.T) {
+	h := newHorspool("ABCAB").(horspool)
+	q := newSunday("ABCAB").(sunday)
+	tests := []struct {
+		c        byte
+		horspool int
+		sunday   int
+	}{
+		{c: 'A', horspool: 1, sunday: 2},
+		{c: 'B', horspool: 3, sunday: 1},
+		{c: 'C', horspool: 2, sunday: 3},
+		{c: 'X', horspool: 5, sunday: 6},
+	}
+
+	for _, tt := range tests {
+		if h.shift[tt.c] != tt.horspool || q.shift[tt.c] != tt.sunday {
+			t.Errorf("%c: expected shifts %d and %d, got %d and %d", tt.c, tt.horspool, tt.sunday, h.shift[tt.c], q.shift[tt.c])
+		}
+	}
+}
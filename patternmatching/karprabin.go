@@ -0,0 +1,67 @@
+package patternmatching
+
+const (
+	karpRabinBase  = 256 // Number of characters in the alphabet (ASCII)
+	karpRabinPrime = 101 // A prime number for hashing
+)
+
+// karpRabin compares rolling hashes of text windows with the pattern hash and
+// checks the characters only when the hashes are equal.
+type karpRabin struct {
+	pattern     string
+	patternHash int
+	// h is base^(m-1) % prime, used to remove the leading character from the window hash.
+	h int
+	preprocessing
+}
+
+// Karp-Rabin Search with rolling hash
+func newKarpRabin(pattern string) scanner {
+	// Computing h and the pattern hash takes 2m-1 multiplications.
+	k := karpRabin{pattern: pattern, h: 1, preprocessing: preprocessing{cost: 2*len(pattern) - 1}}
+	for i := 0; i < len(pattern)-1; i++ {
+		k.h = (k.h * karpRabinBase) % karpRabinPrime
+	}
+	for i := 0; i < len(pattern); i++ {
+		k.patternHash = (karpRabinBase*k.patternHash + int(pattern[i])) % karpRabinPrime
+	}
+	return k
+}
+
+func (k karpRabin) scan(text string, s *search) {
+	m := len(k.pattern)
+	n := len(text)
+	if m == 0 || n == 0 || m > n {
+		return
+	}
+
+	textHash := 0
+	for i := 0; i < m; i++ {
+		textHash = (karpRabinBase*textHash + int(text[i])) % karpRabinPrime
+	}
+	s.stats.HashComputations++
+
+	for i := 0; i <= n-m; i++ {
+		s.stats.Comparisons++
+		s.compareHash(i, k.patternHash == textHash)
+		// Check character-by-character only if hashes match
+		if k.patternHash == textHash {
+			if text[i:i+m] != k.pattern {
+				s.stats.SpuriousHits++
+			} else if !s.report(i) {
+				return
+			}
+			s.shift(i, i+1, ShiftNext)
+		} else {
+			s.shift(i, i+1, ShiftHashMismatch)
+		}
+		// Calculate hash for next window
+		if i < n-m {
+			textHash = (karpRabinBase*(textHash-int(text[i])*k.h) + int(text[i+m])) % karpRabinPrime
+			if textHash < 0 {
+				textHash += karpRabinPrime
+			}
+			s.stats.HashComputations++
+		}
+	}
+}
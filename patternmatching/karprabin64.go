@@ -0,0 +1,240 @@
+package patternmatching
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"sort"
+)
+
+// mersenne61 is the prime 2^61-1. Reducing modulo a Mersenne prime needs only
+// shifts and additions, and with a random base two different windows of length m
+// collide with probability below m/2^61.
+const mersenne61 = 1<<61 - 1
+
+// rollingHash computes polynomial hashes modulo 2^61-1.
+type rollingHash struct {
+	base uint64
+}
+
+// newRollingHash picks a random base, so no fixed input can be crafted to
+// produce collisions.
+func newRollingHash() rollingHash {
+	return rollingHash{base: 256 + rand.Uint64N(mersenne61-256)}
+}
+
+// mulMod returns a*b mod 2^61-1 for a, b < 2^61-1.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	// a*b = hi*2^64 + lo, and 2^61 = 1 (mod 2^61-1).
+	sum := (hi<<3 | lo>>61) + lo&mersenne61
+	sum = sum&mersenne61 + sum>>61
+	if sum >= mersenne61 {
+		sum -= mersenne61
+	}
+	return sum
+}
+
+func addMod(a, b uint64) uint64 {
+	sum := a + b
+	if sum >= mersenne61 {
+		sum -= mersenne61
+	}
+	return sum
+}
+
+func subMod(a, b uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+	return a + mersenne61 - b
+}
+
+// hash returns the hash of s.
+func (r rollingHash) hash(s string) uint64 {
+	h := uint64(0)
+	for i := 0; i < len(s); i++ {
+		h = addMod(mulMod(h, r.base), uint64(s[i]))
+	}
+	return h
+}
+
+// power returns base^n, the weight of the character leaving a window of length n.
+func (r rollingHash) power(n int) uint64 {
+	p := uint64(1)
+	for i := 0; i < n; i++ {
+		p = mulMod(p, r.base)
+	}
+	return p
+}
+
+// roll moves the window hash h one character to the right: out leaves the window
+// and in enters it. leaving is base^m for windows of length m.
+func (r rollingHash) roll(h uint64, out, in byte, leaving uint64) uint64 {
+	h = addMod(mulMod(h, r.base), uint64(in))
+	return subMod(h, mulMod(uint64(out), leaving))
+}
+
+// karpRabin64 is Karp-Rabin with 64-bit arithmetic modulo 2^61-1 and a random base,
+// instead of the prime 101 of the classic version.
+type karpRabin64 struct {
+	pattern     string
+	hasher      rollingHash
+	patternHash uint64
+	leaving     uint64
+	preprocessing
+}
+
+// Karp-Rabin Search with a 64-bit rolling hash
+func newKarpRabin64(pattern string) scanner {
+	return newKarpRabin64WithHash(pattern, newRollingHash())
+}
+
+func newKarpRabin64WithHash(pattern string, hasher rollingHash) karpRabin64 {
+	return karpRabin64{
+		pattern:     pattern,
+		hasher:      hasher,
+		patternHash: hasher.hash(pattern),
+		leaving:     hasher.power(len(pattern)),
+		// The pattern hash and base^m take m multiplications each.
+		preprocessing: preprocessing{cost: 2 * len(pattern)},
+	}
+}
+
+func (k karpRabin64) scan(text string, s *search) {
+	m := len(k.pattern)
+	n := len(text)
+	if m > n {
+		return
+	}
+
+	textHash := k.hasher.hash(text[:m])
+	s.stats.HashComputations++
+	for i := 0; i <= n-m; i++ {
+		s.stats.Comparisons++
+		s.compareHash(i, k.patternHash == textHash)
+		if k.patternHash == textHash {
+			if text[i:i+m] != k.pattern {
+				s.stats.SpuriousHits++
+			} else if !s.report(i) {
+				return
+			}
+			s.shift(i, i+1, ShiftNext)
+		} else {
+			s.shift(i, i+1, ShiftHashMismatch)
+		}
+		if i < n-m {
+			textHash = k.hasher.roll(textHash, text[i], text[i+m], k.leaving)
+			s.stats.HashComputations++
+		}
+	}
+}
+
+// MultiKarpRabin searches for many patterns at once with rolling hashes.
+//
+// The patterns are grouped by length. For every length a single rolling hash is
+// kept over the text, and the hash of each window is looked up in the set of
+// pattern hashes of that length, so the cost per text position depends on the
+// number of distinct lengths, not on the number of patterns.
+type MultiKarpRabin struct {
+	patterns []string
+	hasher   rollingHash
+	// groups are ordered by decreasing pattern length.
+	groups []lengthGroup
+	// tables counts the multiplications hashing the patterns, and the hash and
+	// pattern index stored for every pattern.
+	tables preprocessing
+}
+
+type lengthGroup struct {
+	length  int
+	leaving uint64
+	// patternIDs maps a hash to the patterns of this length having it.
+	patternIDs map[uint64][]int
+}
+
+// NewMultiKarpRabin prepares the hash sets for the given patterns.
+func NewMultiKarpRabin(patterns []string) (*MultiKarpRabin, error) {
+	return newMultiKarpRabin(patterns, newRollingHash())
+}
+
+func newMultiKarpRabin(patterns []string, hasher rollingHash) (*MultiKarpRabin, error) {
+	if len(patterns) == 0 {
+		return nil, ErrNoPatterns
+	}
+
+	mk := &MultiKarpRabin{patterns: append([]string(nil), patterns...), hasher: hasher}
+	byLength := make(map[int]*lengthGroup)
+	for id, pattern := range patterns {
+		if pattern == "" {
+			return nil, ErrEmptyPattern
+		}
+		group, ok := byLength[len(pattern)]
+		if !ok {
+			group = &lengthGroup{length: len(pattern), leaving: hasher.power(len(pattern)), patternIDs: map[uint64][]int{}}
+			byLength[len(pattern)] = group
+			mk.tables.cost += len(pattern)
+		}
+		h := hasher.hash(pattern)
+		group.patternIDs[h] = append(group.patternIDs[h], id)
+		mk.tables.cost += len(pattern)
+		mk.tables.memory += 8 + intBytes
+	}
+
+	for _, group := range byLength {
+		mk.groups = append(mk.groups, *group)
+	}
+	sort.Slice(mk.groups, func(i, j int) bool { return mk.groups[i].length > mk.groups[j].length })
+	return mk, nil
+}
+
+// Patterns returns the patterns the searcher was built from.
+func (mk *MultiKarpRabin) Patterns() []string {
+	return append([]string(nil), mk.patterns...)
+}
+
+// FindAll returns every occurrence of every pattern, ordered by end offset.
+// Occurrences ending at the same offset are reported longest pattern first,
+// like Dictionary.FindAll.
+func (mk *MultiKarpRabin) FindAll(text string) ([]DictionaryMatch, MatchStats) {
+	var matches []DictionaryMatch
+	stats := mk.FindIter(text, func(match DictionaryMatch) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches, stats
+}
+
+// FindIter calls yield for every occurrence until yield returns false. Every hash
+// set lookup counts as one comparison, and every pattern whose hash matches a
+// window it differs from counts as a spurious hit. Every update of the window
+// hash of a length counts as a hash computation.
+func (mk *MultiKarpRabin) FindIter(text string, yield func(DictionaryMatch) bool) MatchStats {
+	stats := mk.tables.stats()
+	hashes := make([]uint64, len(mk.groups))
+
+	for end := 1; end <= len(text); end++ {
+		for g := range mk.groups {
+			group := &mk.groups[g]
+			start := end - group.length
+			if start > 0 {
+				hashes[g] = mk.hasher.roll(hashes[g], text[start-1], text[end-1], group.leaving)
+			} else {
+				hashes[g] = addMod(mulMod(hashes[g], mk.hasher.base), uint64(text[end-1]))
+			}
+			stats.HashComputations++
+			if start < 0 {
+				continue
+			}
+
+			stats.Comparisons++
+			for _, id := range group.patternIDs[hashes[g]] {
+				if text[start:end] != mk.patterns[id] {
+					stats.SpuriousHits++
+				} else if !yield(DictionaryMatch{PatternID: id, Start: start, End: end}) {
+					return stats
+				}
+			}
+		}
+	}
+	return stats
+}
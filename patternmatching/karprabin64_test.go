@@ -0,0 +1,107 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMulMod(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b := random.Uint64()%mersenne61, random.Uint64()%mersenne61
+		expected := uint64(0)
+		// Double-and-add keeps every intermediate value below 2^62.
+		for bit := 60; bit >= 0; bit-- {
+			expected = (expected * 2) % mersenne61
+			if b&(1<<bit) != 0 {
+				expected = (expected + a) % mersenne61
+			}
+		}
+		if got := mulMod(a, b); got != expected {
+			t.Fatalf("mulMod(%d, %d): expected %d, got %d", a, b, expected, got)
+		}
+	}
+}
+
+func TestKarpRabin64MatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		text := randomText(random, random.Intn(500), "ab")
+		pattern := randomText(random, 1+random.Intn(12), "ab")
+
+		expected, _ := MustCompile(pattern, BruteForce).FindAll(text)
+		matches, stats := MustCompile(pattern, KarpRabin64).FindAll(text)
+		if !reflect.DeepEqual(matches, expected) {
+			t.Fatalf("%q in %q: expected %v, got %v", pattern, text, expected, matches)
+		}
+		if stats.SpuriousHits != 0 {
+			t.Fatalf("%q in %q: unexpected %d spurious hits", pattern, text, stats.SpuriousHits)
+		}
+	}
+}
+
+func TestKarpRabinSpuriousHits(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	text := randomText(random, 100000, "ACGT")
+	pattern := "GATTACAGATTACA"
+
+	_, classic := MustCompile(pattern, KarpRabin).Count(text)
+	_, wide := MustCompile(pattern, KarpRabin64).Count(text)
+	if classic.SpuriousHits < 500 {
+		t.Errorf("expected the modulus 101 to collide often, got %d spurious hits", classic.SpuriousHits)
+	}
+	if wide.SpuriousHits != 0 {
+		t.Errorf("expected no collisions modulo 2^61-1, got %d", wide.SpuriousHits)
+	}
+	if classic.Comparisons != wide.Comparisons {
+		t.Errorf("expected one comparison per window in both, got %d and %d", classic.Comparisons, wide.Comparisons)
+	}
+}
+
+func TestMultiKarpRabinMatchesDictionary(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		text := randomText(random, random.Intn(400), "abc")
+		patterns := make([]string, 1+random.Intn(10))
+		for i := range patterns {
+			patterns[i] = randomText(random, 1+random.Intn(5), "abc")
+		}
+
+		dictionary, err := NewDictionary(patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		multi, err := NewMultiKarpRabin(patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := dictionary.FindAll(text)
+		matches, stats := multi.FindAll(text)
+		if !reflect.DeepEqual(matches, expected) {
+			t.Fatalf("%q in %q: expected %v, got %v", patterns, text, expected, matches)
+		}
+		if stats.SpuriousHits != 0 {
+			t.Fatalf("unexpected %d spurious hits", stats.SpuriousHits)
+		}
+	}
+}
+
+func TestMultiKarpRabinCountsSpuriousHits(t *testing.T) {
+	// With base 1 every permutation of the pattern has the same hash.
+	multi, err := newMultiKarpRabin([]string{"abc", "xy"}, rollingHash{base: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, stats := multi.FindAll("cba abc yx")
+	if expected := []DictionaryMatch{{PatternID: 0, Start: 4, End: 7}}; !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected %v, got %v", expected, matches)
+	}
+	if stats.SpuriousHits != 2 {
+		t.Errorf("expected 2 spurious hits (cba, yx), got %d", stats.SpuriousHits)
+	}
+	// 8 windows of length 3 and 9 windows of length 2.
+	if stats.Comparisons != 17 {
+		t.Errorf("expected one lookup per window of each length, got %d", stats.Comparisons)
+	}
+}
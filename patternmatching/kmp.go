@@ -0,0 +1,129 @@
+package patternmatching
+
+// prefixScanner implements Morris-Pratt and Knuth-Morris-Pratt on strings. Like
+// the other scanners shared with slices, it searches the bytes of the text: the
+// compiler does not copy a string converted to []byte which is only read.
+type prefixScanner struct {
+	prefixSearch[byte]
+}
+
+// prefixSearch implements Morris-Pratt and Knuth-Morris-Pratt on strings and
+// slices. Both use the LPS table to fall back after a mismatch; they differ in
+// what happens after a full match.
+type prefixSearch[T comparable] struct {
+	pattern []T
+	lps     []int
+	// resetOnMatch restarts the pattern from scratch after a match
+	// (Morris-Pratt) instead of falling back to lps[m-1] (Knuth-Morris-Pratt).
+	resetOnMatch bool
+	preprocessing
+}
+
+// Morris-Pratt Search
+func newMorrisPratt(pattern string) scanner {
+	p := newPrefixScanner(pattern)
+	p.resetOnMatch = true
+	return p
+}
+
+// Knuth-Morris-Pratt Search
+func newKnuthMorrisPratt(pattern string) scanner {
+	return newPrefixScanner(pattern)
+}
+
+func newPrefixScanner(pattern string) prefixScanner {
+	return prefixScanner{newPrefixSearch([]byte(pattern))}
+}
+
+func newPrefixSearch[T comparable](pattern []T) prefixSearch[T] {
+	lps, comparisons := computeLPS(pattern)
+	return prefixSearch[T]{pattern: pattern, lps: lps, preprocessing: preprocessing{cost: comparisons, memory: len(lps) * intBytes}}
+}
+
+// overlapping turns Morris-Pratt into Knuth-Morris-Pratt.
+func (p prefixScanner) overlapping() scanner {
+	p.resetOnMatch = false
+	return p
+}
+
+func (p prefixScanner) scan(text string, s *search) {
+	p.resume([]byte(text), 0, s)
+}
+
+// resume scans text when the previous piece of a stream ended with the first j
+// characters of the pattern matched. Matches starting in the previous piece are
+// reported with negative offsets. It returns the number of characters matched at
+// the end of text and whether the scan should go on.
+func (p prefixSearch[T]) resume(text []T, j int, s *search) (int, bool) {
+	m := len(p.pattern)
+	n := len(text)
+
+	i := 0
+	for i < n {
+		s.stats.Comparisons++
+		equal := text[i] == p.pattern[j]
+		s.compare(i-j, i, j, equal)
+		if equal {
+			i++
+			j++
+			if j == m {
+				if !s.report(i - m) {
+					return j, false
+				}
+				if p.resetOnMatch {
+					s.shift(i-m, i, ShiftAfterMatch)
+					j = 0
+				} else {
+					s.shift(i-m, i-p.lps[j-1], ShiftLPS)
+					j = p.lps[j-1] // Use LPS fallback after full match
+				}
+			}
+		} else {
+			if j != 0 {
+				s.shift(i-j, i-p.lps[j-1], ShiftLPS)
+				j = p.lps[j-1]
+			} else {
+				s.shift(i, i+1, ShiftNext)
+				i++
+			}
+		}
+	}
+	return j, true
+}
+
+// computeLPS returns, for every prefix of the pattern, the length of its
+// longest proper prefix which is also a suffix, and the number of comparisons
+// it took.
+func computeLPS[T comparable](pattern []T) ([]int, int) {
+	m := len(pattern)
+	lps := make([]int, m)
+	length := 0
+	comparisons := 0
+	i := 1
+
+	for i < m {
+		comparisons++
+		if pattern[i] == pattern[length] {
+			length++
+			lps[i] = length
+			i++
+		} else {
+			if length != 0 {
+				length = lps[length-1]
+			} else {
+				lps[i] = 0
+				i++
+			}
+		}
+	}
+	return lps, comparisons
+}
+
+// PrefixFunction returns the failure table Knuth-Morris-Pratt builds for s: for
+// every prefix s[:i+1], the length of its longest proper border, a prefix which
+// is also a suffix. The borders of s describe its periods; see the stringology
+// package.
+func PrefixFunction(s string) []int {
+	lps, _ := computeLPS([]byte(s))
+	return lps
+}
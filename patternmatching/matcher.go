@@ -0,0 +1,282 @@
+// Package patternmatching implements classic exact string matching algorithms
+// behind a common Matcher interface.
+//
+// Every search reports where the pattern occurs together with statistics about
+// the work the algorithm did (the number of character comparisons), so the
+// algorithms can be compared with each other on the same input.
+package patternmatching
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Algorithm names a string matching algorithm that can be passed to Compile.
+type Algorithm string
+
+const (
+	BruteForce          Algorithm = "brute-force"
+	MorrisPratt         Algorithm = "morris-pratt"
+	KnuthMorrisPratt    Algorithm = "knuth-morris-pratt"
+	KarpRabin           Algorithm = "karp-rabin"
+	KarpRabin64         Algorithm = "karp-rabin-64"
+	BoyerMoore          Algorithm = "boyer-moore"
+	BoyerMooreOptimized Algorithm = "boyer-moore-optimized"
+	Horspool            Algorithm = "horspool"
+	Sunday              Algorithm = "sunday"
+	AhoCorasick         Algorithm = "aho-corasick"
+	ZAlgorithm          Algorithm = "z-algorithm"
+	TwoWay              Algorithm = "two-way"
+)
+
+// ErrEmptyPattern is returned by Compile when the pattern is empty.
+var ErrEmptyPattern = errors.New("patternmatching: empty pattern")
+
+// algorithms maps every supported algorithm to the function preparing its
+// pattern tables.
+var algorithms = map[Algorithm]func(pattern string) scanner{
+	BruteForce:          newBruteForce,
+	MorrisPratt:         newMorrisPratt,
+	KnuthMorrisPratt:    newKnuthMorrisPratt,
+	KarpRabin:           newKarpRabin,
+	KarpRabin64:         newKarpRabin64,
+	BoyerMoore:          newBoyerMoore,
+	BoyerMooreOptimized: newBoyerMooreOptimized,
+	Horspool:            newHorspool,
+	Sunday:              newSunday,
+	AhoCorasick:         newAhoCorasick,
+	ZAlgorithm:          newZAlgorithm,
+	TwoWay:              newTwoWay,
+}
+
+// aliases are the short names accepted by ParseAlgorithm.
+var aliases = map[string]Algorithm{
+	"bf":   BruteForce,
+	"mp":   MorrisPratt,
+	"kmp":  KnuthMorrisPratt,
+	"kr":   KarpRabin,
+	"rk":   KarpRabin,
+	"kr64": KarpRabin64,
+	"rk64": KarpRabin64,
+	"bm":   BoyerMoore,
+	"bmo":  BoyerMooreOptimized,
+	"bmh":  Horspool,
+	"qs":   Sunday,
+	"ac":   AhoCorasick,
+	"z":    ZAlgorithm,
+	"tw":   TwoWay,
+}
+
+// Algorithms returns all supported algorithms sorted by name.
+func Algorithms() []Algorithm {
+	list := make([]Algorithm, 0, len(algorithms))
+	for algo := range algorithms {
+		list = append(list, algo)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// ParseAlgorithm resolves an algorithm by its full name (e.g. "knuth-morris-pratt")
+// or its short alias (e.g. "kmp"). The lookup is case-insensitive.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if algo, ok := aliases[name]; ok {
+		return algo, nil
+	}
+	if _, ok := algorithms[Algorithm(name)]; ok {
+		return Algorithm(name), nil
+	}
+	return "", fmt.Errorf("patternmatching: unknown algorithm %q", name)
+}
+
+// Match is a single occurrence of the pattern, as byte offsets into the text.
+// The matched text is text[Start:End]. The rune offsets are only reported by
+// matchers compiled with one of the Unicode options and are zero otherwise.
+type Match struct {
+	Start     int
+	End       int
+	RuneStart int
+	RuneEnd   int
+}
+
+// Matcher searches a text for a pattern compiled ahead of time.
+// Each method reports the statistics of the search it performed.
+//
+// The tables of the algorithm are built once by Compile and only read by the
+// searches, so a Matcher is immutable and may be shared by many goroutines.
+type Matcher interface {
+	// Pattern returns the pattern the matcher was compiled from.
+	Pattern() string
+	// Algorithm returns the algorithm used by the matcher.
+	Algorithm() Algorithm
+	// Options returns the matching modes the matcher was compiled with.
+	Options() Options
+	// FindAll returns every match in text, in order of their start offsets.
+	FindAll(text string) ([]Match, MatchStats)
+	// FindFirst returns the leftmost match. The search stops as soon as it is found.
+	FindFirst(text string) (Match, bool, MatchStats)
+	// Count returns the number of matches in text.
+	Count(text string) (int, MatchStats)
+	// FindIter calls yield for every match until yield returns false.
+	FindIter(text string, yield func(Match) bool) MatchStats
+}
+
+// Compile prepares pattern for searching with the given algorithm and matching modes.
+func Compile(pattern string, algo Algorithm, opts ...Option) (Matcher, error) {
+	newScanner, ok := algorithms[algo]
+	if !ok {
+		return nil, fmt.Errorf("patternmatching: unknown algorithm %q", algo)
+	}
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+
+	m := &matcher{pattern: pattern, algo: algo, needle: pattern}
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
+	if m.opts.unicode() {
+		m.needle = transform(pattern, m.opts).text
+	}
+	m.width = len(m.needle)
+	m.scanner = newScanner(m.needle)
+	// NonOverlapping filters every occurrence in FindIter: a scanner skipping past
+	// a match could miss the next one when a Unicode candidate is rejected.
+	if o, ok := m.scanner.(overlapper); ok && m.opts.Overlap != OverlapDefault {
+		m.scanner = o.overlapping()
+	}
+	return m, nil
+}
+
+// MustCompile is like Compile but panics if the pattern cannot be compiled.
+func MustCompile(pattern string, algo Algorithm, opts ...Option) Matcher {
+	m, err := Compile(pattern, algo, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// scanner is implemented by every algorithm. scan walks the text and reports
+// the start offset of each match to s until s asks it to stop; tables describes
+// what building its pattern tables cost.
+type scanner interface {
+	scan(text string, s *search)
+	tables() preprocessing
+}
+
+// search carries the state of a single scan: the statistics collected so far,
+// the consumer of the matches and, when tracing, the trace being recorded.
+type search struct {
+	stats  MatchStats
+	yield  func(start int) bool
+	tracer *Trace
+}
+
+// report passes a match to the consumer and tells whether the scan should go on.
+func (s *search) report(start int) bool {
+	return s.yield(start)
+}
+
+// compare records the comparison of text[textIndex] with pattern[patternIndex]
+// while the pattern is aligned at alignment.
+func (s *search) compare(alignment, textIndex, patternIndex int, equal bool) {
+	if s.tracer != nil {
+		s.tracer.add(Event{Kind: EventCompare, Alignment: alignment, TextIndex: textIndex, PatternIndex: patternIndex, Equal: equal})
+	}
+}
+
+// compareHash records the comparison of the hash of the window at alignment with
+// the pattern hash.
+func (s *search) compareHash(alignment int, equal bool) {
+	if s.tracer != nil {
+		s.tracer.add(Event{Kind: EventHash, Alignment: alignment, Equal: equal})
+	}
+}
+
+// shift records the pattern moving from one alignment to another.
+func (s *search) shift(from, to int, reason ShiftReason) {
+	s.stats.Shifts++
+	s.stats.ShiftDistance += to - from
+	if s.tracer != nil {
+		s.tracer.add(Event{Kind: EventShift, Alignment: to, Shift: to - from, Reason: reason})
+	}
+}
+
+type matcher struct {
+	pattern string
+	algo    Algorithm
+	opts    Options
+	// needle is the pattern the scanner searches for, transformed by the options.
+	needle string
+	// width is the length of every match in the searched text.
+	width   int
+	scanner scanner
+}
+
+func (m *matcher) Pattern() string {
+	return m.pattern
+}
+
+func (m *matcher) Algorithm() Algorithm {
+	return m.algo
+}
+
+func (m *matcher) Options() Options {
+	return m.opts
+}
+
+func (m *matcher) FindIter(text string, yield func(Match) bool) MatchStats {
+	if m.opts.Overlap == NonOverlapping {
+		yield = nonOverlapping(yield)
+	}
+	if !m.opts.unicode() {
+		s := search{stats: m.scanner.tables().stats(), yield: func(start int) bool {
+			return yield(Match{Start: start, End: start + m.width})
+		}}
+		m.scanner.scan(text, &s)
+		return s.stats
+	}
+
+	transformed := transform(text, m.opts)
+	s := search{stats: m.scanner.tables().stats(), yield: func(start int) bool {
+		match, ok := transformed.original(start, start+m.width)
+		if !ok {
+			return true
+		}
+		return yield(match)
+	}}
+	m.scanner.scan(transformed.text, &s)
+	return s.stats
+}
+
+func (m *matcher) FindAll(text string) ([]Match, MatchStats) {
+	var matches []Match
+	stats := m.FindIter(text, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches, stats
+}
+
+func (m *matcher) FindFirst(text string) (Match, bool, MatchStats) {
+	var first Match
+	found := false
+	stats := m.FindIter(text, func(match Match) bool {
+		first, found = match, true
+		return false
+	})
+	return first, found, stats
+}
+
+func (m *matcher) Count(text string) (int, MatchStats) {
+	count := 0
+	stats := m.FindIter(text, func(Match) bool {
+		count++
+		return true
+	})
+	return count, stats
+}
@@ -0,0 +1,138 @@
+package patternmatching
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFindAllReportsMatchPositions(t *testing.T) {
+	tests := []struct {
+		text    string
+		pattern string
+		// expected start offsets, per algorithm where the overlap handling differs
+		expected    []int
+		morrisPratt []int
+		boyerMoore  []int
+	}{
+		{
+			text:     "THIS IS A SIMPLE EXAMPLE",
+			pattern:  "SIMPLE",
+			expected: []int{10},
+		},
+		{
+			text:     "THIS TEST WILL HAVE MULTIPLE MATCHES, SO THAT WE CAN TEST. ONE MORE TEST.",
+			pattern:  "TEST",
+			expected: []int{5, 53, 68},
+		},
+		{
+			text:     "NO MATCH HERE",
+			pattern:  "ABSENT",
+			expected: nil,
+		},
+		{
+			text:        "AAAAA",
+			pattern:     "AA",
+			expected:    []int{0, 1, 2, 3},
+			morrisPratt: []int{0, 2},
+			boyerMoore:  []int{0, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, algo := range Algorithms() {
+			t.Run(string(algo)+"/"+tt.pattern, func(t *testing.T) {
+				expected := tt.expected
+				switch {
+				case algo == MorrisPratt && tt.morrisPratt != nil:
+					expected = tt.morrisPratt
+				case algo == BoyerMoore && tt.boyerMoore != nil:
+					expected = tt.boyerMoore
+				}
+
+				matches, stats := MustCompile(tt.pattern, algo).FindAll(tt.text)
+				var starts []int
+				for _, match := range matches {
+					starts = append(starts, match.Start)
+					if tt.text[match.Start:match.End] != tt.pattern {
+						t.Errorf("match %v does not cover the pattern", match)
+					}
+				}
+				if !reflect.DeepEqual(starts, expected) {
+					t.Errorf("expected matches at %v, got %v", expected, starts)
+				}
+				if stats.Comparisons == 0 {
+					t.Errorf("expected comparisons to be counted")
+				}
+			})
+		}
+	}
+}
+
+func TestFindFirstStopsAtFirstMatch(t *testing.T) {
+	text := "TEST ONE, TEST TWO, TEST THREE"
+	for _, algo := range Algorithms() {
+		m := MustCompile("TEST", algo)
+		_, allStats := m.FindAll(text)
+
+		match, found, stats := m.FindFirst(text)
+		if !found || match != (Match{Start: 0, End: 4}) {
+			t.Errorf("%s: expected first match at 0, got %v (found=%t)", algo, match, found)
+		}
+		if stats.Comparisons >= allStats.Comparisons {
+			t.Errorf("%s: expected FindFirst to stop early, got %d comparisons (FindAll: %d)", algo, stats.Comparisons, allStats.Comparisons)
+		}
+
+		if _, found, _ := m.FindFirst("NOTHING"); found {
+			t.Errorf("%s: expected no match", algo)
+		}
+	}
+}
+
+func TestCountAndFindIter(t *testing.T) {
+	text := "COMPUTER SCIENCE IS NO MORE ABOUT COMPUTERS THAN ASTRONOMY IS ABOUT TELESCOPES"
+	for _, algo := range Algorithms() {
+		m := MustCompile("NO", algo)
+
+		count, countStats := m.Count(text)
+		if count != 2 {
+			t.Errorf("%s: expected 2 matches, got %d", algo, count)
+		}
+
+		var seen []Match
+		iterStats := m.FindIter(text, func(match Match) bool {
+			seen = append(seen, match)
+			return true
+		})
+		if len(seen) != count || iterStats != countStats {
+			t.Errorf("%s: FindIter saw %d matches (%+v), Count saw %d (%+v)", algo, len(seen), iterStats, count, countStats)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	if _, err := Compile("", KnuthMorrisPratt); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+	if _, err := Compile("abc", Algorithm("unknown")); err == nil {
+		t.Errorf("expected an error for an unknown algorithm")
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	tests := map[string]Algorithm{
+		"kmp":                   KnuthMorrisPratt,
+		"BM":                    BoyerMoore,
+		"rk":                    KarpRabin,
+		"boyer-moore-optimized": BoyerMooreOptimized,
+	}
+	for name, expected := range tests {
+		algo, err := ParseAlgorithm(name)
+		if err != nil || algo != expected {
+			t.Errorf("ParseAlgorithm(%q): expected %s, got %s (%v)", name, expected, algo, err)
+		}
+	}
+	if _, err := ParseAlgorithm("nope"); err == nil {
+		t.Errorf("expected an error for an unknown name")
+	}
+}
@@ -0,0 +1,65 @@
+package patternmatching
+
+import (
+	"errors"
+)
+
+// Overlap selects whether the matches reported by a search may overlap.
+type Overlap int
+
+const (
+	// OverlapDefault keeps the behaviour each algorithm is known for: Morris-Pratt
+	// restarts after a match and Boyer-Moore shifts past it, so they report
+	// non-overlapping matches; all the other algorithms report overlapping ones.
+	// The comparison counts in text_pattern_matching_test.go are measured with it.
+	OverlapDefault Overlap = iota
+	// Overlapping reports every occurrence of the pattern, so "AA" occurs three
+	// times in "AAAA", at 0, 1 and 2.
+	Overlapping
+	// NonOverlapping reports the leftmost occurrence, then the leftmost one starting
+	// at or after its end, and so on, so "AA" occurs twice in "AAAA", at 0 and 2.
+	NonOverlapping
+)
+
+// ErrByteOptionsOnly is returned when Unicode options are passed to a compile
+// function searching bytes or slices, where only WithOverlap applies.
+var ErrByteOptionsOnly = errors.New("patternmatching: only WithOverlap applies")
+
+// WithOverlap selects whether the reported matches may overlap. Every algorithm
+// honours both Overlapping and NonOverlapping.
+func WithOverlap(overlap Overlap) Option {
+	return func(o *Options) { o.Overlap = overlap }
+}
+
+// overlapper is implemented by the scanners which skip past whole matches in the
+// default mode. overlapping returns a scanner for the same pattern reporting
+// every match.
+type overlapper interface {
+	overlapping() scanner
+}
+
+// nonOverlapping passes on the matches starting at or after the end of the last
+// match passed on. The matches have to come in order of their start offsets.
+func nonOverlapping(yield func(Match) bool) func(Match) bool {
+	end := 0
+	return func(match Match) bool {
+		if match.Start < end {
+			return true
+		}
+		end = match.End
+		return yield(match)
+	}
+}
+
+// byteOptions applies opts for the compile functions which do not support the
+// Unicode modes.
+func byteOptions(opts []Option) (Options, error) {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.unicode() {
+		return Options{}, ErrByteOptionsOnly
+	}
+	return options, nil
+}
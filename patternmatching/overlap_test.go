@@ -0,0 +1,131 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// everyOccurrence is the reference for the Overlapping mode.
+func everyOccurrence(text, pattern string) []Match {
+	var matches []Match
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if text[i:i+len(pattern)] == pattern {
+			matches = append(matches, Match{Start: i, End: i + len(pattern)})
+		}
+	}
+	return matches
+}
+
+// greedy is the reference for the NonOverlapping mode.
+func greedy(matches []Match) []Match {
+	var kept []Match
+	end := 0
+	for _, match := range matches {
+		if match.Start >= end {
+			kept = append(kept, match)
+			end = match.End
+		}
+	}
+	return kept
+}
+
+// periodicText repeats a short random block with a few random errors, so that
+// patterns cut from it occur many times, often overlapping.
+func periodicText(random *rand.Rand, n int) string {
+	block := randomText(random, 1+random.Intn(3), "ab")
+	text := []byte(strings.Repeat(block, n/len(block)+1)[:n])
+	for i := 0; i < n/20; i++ {
+		text[random.Intn(n)] = "ab"[random.Intn(2)]
+	}
+	return string(text)
+}
+
+func TestOverlapModesAreConsistentAcrossAlgorithms(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		text := periodicText(random, 1+random.Intn(200))
+		start := random.Intn(len(text))
+		pattern := text[start : start+1+random.Intn(min(8, len(text)-start))]
+		every := everyOccurrence(text, pattern)
+		modes := map[Overlap][]Match{Overlapping: every, NonOverlapping: greedy(every)}
+
+		for overlap, expected := range modes {
+			for _, algo := range Algorithms() {
+				matches, _ := MustCompile(pattern, algo, WithOverlap(overlap)).FindAll(text)
+				if !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("%s, mode %d, %q in %q: expected %v, got %v", algo, overlap, pattern, text, expected, matches)
+				}
+			}
+			for _, algo := range []Algorithm{WildcardBruteForce, WildcardShiftAnd} {
+				matcher, err := CompileWildcard(pattern, algo, WithOverlap(overlap))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if matches, _ := matcher.FindAll(text); !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("%s, mode %d, %q in %q: expected %v, got %v", algo, overlap, pattern, text, expected, matches)
+				}
+			}
+			for _, algo := range []Algorithm{MorrisPratt, KnuthMorrisPratt, ZAlgorithm, BoyerMoore, BoyerMooreOptimized} {
+				matches, _ := MustCompileSlice([]byte(pattern), algo, WithOverlap(overlap)).FindAll([]byte(text))
+				if !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("slice %s, mode %d, %q in %q: expected %v, got %v", algo, overlap, pattern, text, expected, matches)
+				}
+			}
+			parallel, _, err := FindAllParallel(MustCompile(pattern, BoyerMoore, WithOverlap(overlap)), text, 7, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(parallel, expected) {
+				t.Fatalf("parallel, mode %d, %q in %q: expected %v, got %v", overlap, pattern, text, expected, parallel)
+			}
+		}
+	}
+}
+
+func TestOverlapDefaultKeepsLegacyResults(t *testing.T) {
+	text := "AAAAA"
+	tests := []struct {
+		algo     Algorithm
+		expected int
+	}{
+		{MorrisPratt, 2},
+		{BoyerMoore, 2},
+		{KnuthMorrisPratt, 4},
+		{BoyerMooreOptimized, 4},
+	}
+	for _, tt := range tests {
+		count, stats := MustCompile("AA", tt.algo).Count(text)
+		if count != tt.expected {
+			t.Errorf("%s: expected %d matches, got %d", tt.algo, tt.expected, count)
+		}
+		_, legacy := MustCompile("AA", tt.algo, WithOverlap(OverlapDefault)).Count(text)
+		if stats != legacy {
+			t.Errorf("%s: expected %+v with OverlapDefault, got %+v", tt.algo, stats, legacy)
+		}
+	}
+}
+
+func TestOverlapRejectsUnicodeOptionsForBytes(t *testing.T) {
+	if _, err := CompileWildcard("a?c", WildcardShiftAnd, WithCaseFolding()); !errors.Is(err, ErrByteOptionsOnly) {
+		t.Errorf("expected ErrByteOptionsOnly, got %v", err)
+	}
+	if _, err := CompileSlice([]byte("abc"), KnuthMorrisPratt, WithCaseFolding()); !errors.Is(err, ErrByteOptionsOnly) {
+		t.Errorf("expected ErrByteOptionsOnly, got %v", err)
+	}
+}
+
+func TestOverlapWithUnicodeOptions(t *testing.T) {
+	text := "ßSSss"
+	every := MustCompile("ss", BoyerMoore, WithCaseFolding(), WithOverlap(Overlapping))
+	matches, _ := every.FindAll(text)
+	if len(matches) != 3 {
+		t.Errorf("expected 3 overlapping matches, got %v", matches)
+	}
+	greedy := MustCompile("ss", MorrisPratt, WithCaseFolding(), WithOverlap(NonOverlapping))
+	if count, _ := greedy.Count(text); count != 2 {
+		t.Errorf("expected 2 non-overlapping matches, got %d", count)
+	}
+}
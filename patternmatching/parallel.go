@@ -0,0 +1,109 @@
+package patternmatching
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// DefaultShardSize is the number of text bytes a shard owns by default in FindAllParallel.
+const DefaultShardSize = 1 << 20
+
+// ErrParallelUnsupported is returned by FindAllParallel for matchers compiled with
+// Unicode options, whose matches may be arbitrarily longer than the pattern.
+var ErrParallelUnsupported = errors.New("patternmatching: matcher cannot be searched in parallel")
+
+// shardResult holds what one worker found in one shard.
+type shardResult struct {
+	matches []Match
+	stats   MatchStats
+}
+
+// FindAllParallel searches a large text on a pool of workers goroutines.
+//
+// The text is split into shards owning shardSize bytes each. A shard is searched
+// together with the first m-1 bytes of the next one, where m is the pattern length,
+// so a match starting in the shard is always found by it. Matches starting in the
+// overlap belong to the next shard and are dropped, which removes the duplicates.
+// The matches are returned in order and the statistics are summed over all shards,
+// including the comparisons spent on the overlaps.
+//
+// Each shard starts a new scan, so in the OverlapDefault mode the algorithms
+// reporting only non-overlapping matches (Morris-Pratt, Boyer-Moore) may report
+// overlapping matches across shard boundaries. The Overlapping and NonOverlapping
+// modes give the same matches as FindAll: for NonOverlapping the shards report
+// every occurrence and the merged list is filtered.
+//
+// A shardSize or workers of 0 or less selects DefaultShardSize and GOMAXPROCS.
+func FindAllParallel(m Matcher, text string, shardSize, workers int) ([]Match, MatchStats, error) {
+	if m.Options().unicode() {
+		return nil, MatchStats{}, ErrParallelUnsupported
+	}
+	if shardSize <= 0 {
+		shardSize = DefaultShardSize
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	// The pattern is never shorter than a match: wildcard patterns only get longer
+	// by their syntax.
+	overlap := len(m.Pattern()) - 1
+	search := m
+	if mm, ok := m.(*matcher); ok && mm.opts.Overlap == NonOverlapping {
+		every := *mm
+		every.opts.Overlap = Overlapping
+		search = &every
+	}
+
+	shards := (len(text) + shardSize - 1) / shardSize
+	results := make([]shardResult, shards)
+	jobs := make(chan int, shards)
+	for shard := 0; shard < shards; shard++ {
+		jobs <- shard
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, shards); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for shard := range jobs {
+				results[shard] = searchShard(search, text, shard*shardSize, shardSize, overlap)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var matches []Match
+	var stats MatchStats
+	keep := func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	}
+	if search != m {
+		keep = nonOverlapping(keep)
+	}
+	for _, result := range results {
+		for _, match := range result.matches {
+			keep(match)
+		}
+		stats.Add(result.stats)
+	}
+	return matches, stats, nil
+}
+
+// searchShard searches text[start:start+size] extended by overlap bytes and keeps
+// the matches starting in the shard, moved to offsets in the whole text.
+func searchShard(m Matcher, text string, start, size, overlap int) shardResult {
+	end := min(start+size, len(text))
+	var result shardResult
+	result.stats = m.FindIter(text[start:min(end+overlap, len(text))], func(match Match) bool {
+		if start+match.Start >= end {
+			return false
+		}
+		result.matches = append(result.matches, Match{Start: start + match.Start, End: start + match.End})
+		return true
+	})
+	return result
+}
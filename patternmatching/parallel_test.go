@@ -0,0 +1,60 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestFindAllParallelMatchesSequentialSearch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	// These algorithms report overlapping matches, so sharding cannot change the result.
+	algorithms := []Algorithm{BruteForce, KnuthMorrisPratt, KarpRabin, BoyerMooreOptimized, AhoCorasick}
+
+	for round := 0; round < 30; round++ {
+		text := randomText(random, random.Intn(2000), "ab")
+		pattern := randomText(random, 1+random.Intn(8), "ab")
+
+		for _, algo := range algorithms {
+			matcher := MustCompile(pattern, algo)
+			expected, expectedStats := matcher.FindAll(text)
+
+			for _, shardSize := range []int{1, 7, 100, 5000} {
+				matches, stats, err := FindAllParallel(matcher, text, shardSize, 4)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(matches, expected) {
+					t.Fatalf("%s, shard size %d, %q: expected %d matches, got %d", algo, shardSize, pattern, len(expected), len(matches))
+				}
+				if shardSize >= len(text) && stats != expectedStats {
+					t.Fatalf("%s: a single shard should cost the same as a sequential search, got %+v and %+v", algo, stats, expectedStats)
+				}
+			}
+		}
+	}
+}
+
+func TestFindAllParallelWildcards(t *testing.T) {
+	text := "AAGCATGTACGAAAGT"
+	matcher, err := CompileWildcard("A?G[CT]", WildcardShiftAnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, _ := matcher.FindAll(text)
+	matches, _, err := FindAllParallel(matcher, text, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("expected %v, got %v", expected, matches)
+	}
+}
+
+func TestFindAllParallelRejectsUnicodeMatchers(t *testing.T) {
+	matcher := MustCompile("k", KnuthMorrisPratt, WithCaseFolding())
+	if _, _, err := FindAllParallel(matcher, "KELVIN", 0, 0); !errors.Is(err, ErrParallelUnsupported) {
+		t.Errorf("expected ErrParallelUnsupported, got %v", err)
+	}
+}
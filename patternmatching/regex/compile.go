@@ -0,0 +1,166 @@
+package regex
+
+// opcode is the operation of an NFA instruction.
+type opcode uint8
+
+const (
+	opByte  opcode = iota // consume one byte of set, go to pc+1
+	opSplit               // go to x and y, x preferred
+	opJmp                 // go to x
+	opSave                // record the offset in slot n, go to pc+1
+	opBegin               // go to pc+1 at the beginning of the text
+	opEnd                 // go to pc+1 at the end of the text
+	opMatch               // the whole expression matched
+)
+
+// inst is one state of the Thompson NFA. Only opByte consumes input; the other
+// instructions are followed without reading a byte.
+type inst struct {
+	op   opcode
+	set  byteSet
+	x, y int
+	n    int
+}
+
+// program is the NFA of an expression, surrounded by the saves of group 0.
+type program struct {
+	insts []inst
+	// slots is the number of capture offsets, two per group including group 0.
+	slots int
+}
+
+// compile translates the syntax tree into an NFA with the Thompson construction:
+// every node becomes a fragment of instructions with a single entry and exit.
+func compile(tree *node, captures int) *program {
+	c := &compiler{}
+	c.emit(inst{op: opSave, n: 0})
+	c.compile(tree)
+	c.emit(inst{op: opSave, n: 1})
+	c.emit(inst{op: opMatch})
+	return &program{insts: c.insts, slots: 2 * (captures + 1)}
+}
+
+type compiler struct {
+	insts []inst
+}
+
+func (c *compiler) emit(i inst) int {
+	c.insts = append(c.insts, i)
+	return len(c.insts) - 1
+}
+
+func (c *compiler) pc() int {
+	return len(c.insts)
+}
+
+// split emits a split to the next instruction and to target, in the order the
+// greediness asks for. It returns the split, so target can be patched later.
+func (c *compiler) split(greedy bool) int {
+	pc := c.emit(inst{op: opSplit})
+	if greedy {
+		c.insts[pc].x = pc + 1
+	} else {
+		c.insts[pc].y = pc + 1
+	}
+	return pc
+}
+
+// patchSplit sets the branch of split pc which was not set by split.
+func (c *compiler) patchSplit(pc, target int, greedy bool) {
+	if greedy {
+		c.insts[pc].y = target
+	} else {
+		c.insts[pc].x = target
+	}
+}
+
+func (c *compiler) compile(n *node) {
+	switch n.kind {
+	case nodeEmpty:
+	case nodeSet:
+		c.emit(inst{op: opByte, set: n.set})
+	case nodeBegin:
+		c.emit(inst{op: opBegin})
+	case nodeEnd:
+		c.emit(inst{op: opEnd})
+	case nodeConcat:
+		for _, child := range n.children {
+			c.compile(child)
+		}
+	case nodeCapture:
+		c.emit(inst{op: opSave, n: 2 * n.index})
+		c.compile(n.children[0])
+		c.emit(inst{op: opSave, n: 2*n.index + 1})
+	case nodeAlt:
+		// split L1, next; L1: a; jmp end; next: split L2, ...; last alternative; end:
+		var jumps []int
+		for i, child := range n.children {
+			if i == len(n.children)-1 {
+				c.compile(child)
+				break
+			}
+			split := c.split(true)
+			c.compile(child)
+			jumps = append(jumps, c.emit(inst{op: opJmp}))
+			c.patchSplit(split, c.pc(), true)
+		}
+		for _, jump := range jumps {
+			c.insts[jump].x = c.pc()
+		}
+	case nodeStar:
+		if nullable(n.children[0]) {
+			// A loop over a body matching the empty string would stop before its
+			// first iteration had set the captures of the body. (x+)? gives the
+			// same matches with the priorities of Perl and Go's regexp.
+			plus := &node{kind: nodePlus, children: n.children, greedy: n.greedy}
+			c.compile(&node{kind: nodeQuest, children: []*node{plus}, greedy: n.greedy})
+			return
+		}
+		// L: split body, end; body; jmp L; end:
+		split := c.split(n.greedy)
+		c.compile(n.children[0])
+		c.emit(inst{op: opJmp, x: split})
+		c.patchSplit(split, c.pc(), n.greedy)
+	case nodePlus:
+		// L: body; split L, end; end:
+		start := c.pc()
+		c.compile(n.children[0])
+		split := c.emit(inst{op: opSplit})
+		if n.greedy {
+			c.insts[split].x, c.insts[split].y = start, split+1
+		} else {
+			c.insts[split].x, c.insts[split].y = split+1, start
+		}
+	case nodeQuest:
+		// split body, end; body; end:
+		split := c.split(n.greedy)
+		c.compile(n.children[0])
+		c.patchSplit(split, c.pc(), n.greedy)
+	}
+}
+
+// nullable tells whether n matches the empty string.
+func nullable(n *node) bool {
+	switch n.kind {
+	case nodeSet:
+		return false
+	case nodeConcat:
+		for _, child := range n.children {
+			if !nullable(child) {
+				return false
+			}
+		}
+		return true
+	case nodeAlt:
+		for _, child := range n.children {
+			if nullable(child) {
+				return true
+			}
+		}
+		return false
+	case nodePlus, nodeCapture:
+		return nullable(n.children[0])
+	}
+	// Empty, assertions, stars and optional nodes.
+	return true
+}
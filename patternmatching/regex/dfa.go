@@ -0,0 +1,189 @@
+package regex
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxDFAStates bounds the states cached by the lazy DFA. When the cache is full
+// it is emptied and the states are built again as they are reached, so pathological
+// expressions still run in linear time, only slower.
+const maxDFAStates = 4096
+
+// dfaState is a set of NFA states. Its transitions are built the first time they
+// are taken and cached, so each is computed at most once per cache generation.
+// A state never changes once built, apart from its transitions, which are read
+// without a lock.
+type dfaState struct {
+	// pcs are the opByte and opEnd instructions of the set, in ascending order.
+	pcs []int
+	// match is set when the set contains opMatch.
+	match bool
+	// matchAtEnd is set when the set matches at the end of the text, following
+	// the end assertions it contains.
+	matchAtEnd bool
+	next       [256]atomic.Pointer[dfaState]
+}
+
+// dfa is the lazily built subset construction of a program for unanchored
+// matching: every transition also adds the start state, so the DFA accepts as
+// soon as any match has ended.
+//
+// Searches run concurrently: they follow the cached transitions without a lock,
+// and only building a missing state locks mu.
+type dfa struct {
+	prog *program
+	// start is the initial state at offset 0, where ^ holds.
+	start atomic.Pointer[dfaState]
+
+	// mu guards the cache and the scratch space of closure.
+	mu     sync.Mutex
+	states map[string]*dfaState
+	// scratch is a generation stamp per instruction used by closure.
+	scratch    []int
+	generation int
+}
+
+func newDFA(prog *program) *dfa {
+	return &dfa{prog: prog, states: make(map[string]*dfaState), scratch: make([]int, len(prog.insts))}
+}
+
+// match runs the DFA over text. It returns whether the expression matches and
+// adds the transitions taken and the states built to stats.
+func (d *dfa) match(text string, stats *Stats) bool {
+	state := d.start.Load()
+	if state == nil {
+		d.mu.Lock()
+		if state = d.start.Load(); state == nil {
+			state = d.state([]int{0}, true, stats)
+			d.start.Store(state)
+		}
+		d.mu.Unlock()
+	}
+	for i := 0; i < len(text); i++ {
+		if state.match {
+			return true
+		}
+		stats.Steps++
+		next := state.next[text[i]].Load()
+		if next == nil {
+			next = d.step(state, text[i], stats)
+		}
+		state = next
+	}
+	return state.match || state.matchAtEnd
+}
+
+// step builds the transition of state on c, unless another search has built it
+// meanwhile.
+func (d *dfa) step(state *dfaState, c byte, stats *Stats) *dfaState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if next := state.next[c].Load(); next != nil {
+		return next
+	}
+
+	var seeds []int
+	for _, pc := range state.pcs {
+		in := &d.prog.insts[pc]
+		if in.op == opByte && in.set.has(c) {
+			seeds = append(seeds, pc+1)
+		}
+	}
+	seeds = append(seeds, 0)
+
+	if len(d.states) >= maxDFAStates {
+		// The states cached so far, state included, are dropped from the cache;
+		// state stays usable until the caller moves on.
+		d.states = make(map[string]*dfaState)
+		d.start.Store(nil)
+	}
+	next := d.state(seeds, false, stats)
+	state.next[c].Store(next)
+	return next
+}
+
+// state returns the cached state for the closure of seeds, building it if needed.
+// atBegin tells whether ^ holds. The caller holds mu.
+func (d *dfa) state(seeds []int, atBegin bool, stats *Stats) *dfaState {
+	pcs, match := d.closure(seeds, atBegin, false)
+	key := stateKey(pcs, match, atBegin)
+	if state, ok := d.states[key]; ok {
+		return state
+	}
+
+	state := &dfaState{pcs: pcs, match: match}
+	for _, pc := range pcs {
+		if d.prog.insts[pc].op == opEnd {
+			if _, ok := d.closure([]int{pc + 1}, atBegin, true); ok {
+				state.matchAtEnd = true
+				break
+			}
+		}
+	}
+	d.states[key] = state
+	stats.States++
+	return state
+}
+
+// closure follows the instructions not consuming input from seeds. It returns
+// the opByte and opEnd instructions reached, sorted, and whether opMatch is
+// reached. With atEnd, end assertions are followed instead of kept.
+func (d *dfa) closure(seeds []int, atBegin, atEnd bool) ([]int, bool) {
+	d.generation++
+	var pcs []int
+	match := false
+	stack := append([]int(nil), seeds...)
+	for len(stack) > 0 {
+		pc := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if d.scratch[pc] == d.generation {
+			continue
+		}
+		d.scratch[pc] = d.generation
+
+		in := &d.prog.insts[pc]
+		switch in.op {
+		case opByte:
+			pcs = append(pcs, pc)
+		case opMatch:
+			match = true
+		case opJmp:
+			stack = append(stack, in.x)
+		case opSplit:
+			stack = append(stack, in.x, in.y)
+		case opSave:
+			stack = append(stack, pc+1)
+		case opBegin:
+			if atBegin {
+				stack = append(stack, pc+1)
+			}
+		case opEnd:
+			if atEnd {
+				stack = append(stack, pc+1)
+			} else {
+				pcs = append(pcs, pc)
+			}
+		}
+	}
+	sort.Ints(pcs)
+	return pcs, match
+}
+
+func stateKey(pcs []int, match, atBegin bool) string {
+	var b strings.Builder
+	if match {
+		b.WriteByte('!')
+	}
+	if atBegin {
+		b.WriteByte('^')
+	}
+	for _, pc := range pcs {
+		b.WriteString(strconv.Itoa(pc))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
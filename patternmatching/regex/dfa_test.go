@@ -0,0 +1,67 @@
+package regex
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMatchDFAReusesStates(t *testing.T) {
+	re := MustCompile("(ab|ac)*d")
+	_, first := re.MatchDFA("abacabd")
+	if first.States == 0 {
+		t.Fatal("the first search built no states")
+	}
+	_, second := re.MatchDFA("abacabd")
+	if second.States != 0 || second.Steps != first.Steps {
+		t.Errorf("expected the second search to reuse the states, got %+v after %+v", second, first)
+	}
+}
+
+func TestMatchDFAResetsFullCache(t *testing.T) {
+	// The position of the a counted from the end needs 2^12 states.
+	re := MustCompile("a[ab][ab][ab][ab][ab][ab][ab][ab][ab][ab][ab]$")
+	random := rand.New(rand.NewSource(1))
+	var text strings.Builder
+	for i := 0; i < 100000; i++ {
+		text.WriteByte("ab"[random.Intn(2)])
+	}
+	text.WriteString("b")
+
+	matched, stats := re.MatchDFA(text.String())
+	if expected := regexp.MustCompile(re.String()).MatchString(text.String()); matched != expected {
+		t.Errorf("expected match %v, got %v", expected, matched)
+	}
+	if len(re.dfa.states) > maxDFAStates {
+		t.Errorf("%d states cached, the cache holds %d", len(re.dfa.states), maxDFAStates)
+	}
+	if stats.Steps != text.Len() {
+		t.Errorf("expected one step per byte, got %d", stats.Steps)
+	}
+}
+
+func TestMatchDFAConcurrent(t *testing.T) {
+	// The cache is emptied many times while the searches run.
+	re := MustCompile("a[ab][ab][ab][ab][ab][ab][ab][ab][ab][ab][ab]$")
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(seed))
+			for i := 0; i < 20; i++ {
+				text := make([]byte, 2000)
+				for j := range text {
+					text[j] = "ab"[random.Intn(2)]
+				}
+				expected, _ := re.Match(string(text))
+				if matched, _ := re.MatchDFA(string(text)); matched != expected {
+					t.Errorf("MatchDFA(%q) = %v, Match says %v", text, matched, expected)
+				}
+			}
+		}(int64(g))
+	}
+	wg.Wait()
+}
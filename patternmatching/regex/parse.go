@@ -0,0 +1,368 @@
+package regex
+
+import (
+	"fmt"
+)
+
+// nodeKind is the type of a node of the syntax tree.
+type nodeKind int
+
+const (
+	nodeEmpty   nodeKind = iota // matches the empty string
+	nodeSet                     // one byte of set
+	nodeConcat                  // children one after another
+	nodeAlt                     // one of the children, the first one preferred
+	nodeStar                    // child zero or more times
+	nodePlus                    // child one or more times
+	nodeQuest                   // child zero or one time
+	nodeCapture                 // child, recording its offsets in group index
+	nodeBegin                   // beginning of the text
+	nodeEnd                     // end of the text
+)
+
+type node struct {
+	kind     nodeKind
+	set      byteSet
+	children []*node
+	// greedy repetitions prefer one more iteration of the child to leaving it.
+	greedy bool
+	index  int
+}
+
+// byteSet is a set of bytes, one bit per byte.
+type byteSet [4]uint64
+
+func (b *byteSet) add(c byte) {
+	b[c/64] |= 1 << (c % 64)
+}
+
+func (b *byteSet) addRange(lo, hi byte) {
+	for c := int(lo); c <= int(hi); c++ {
+		b.add(byte(c))
+	}
+}
+
+func (b *byteSet) addSet(other byteSet) {
+	for i := range b {
+		b[i] |= other[i]
+	}
+}
+
+func (b *byteSet) has(c byte) bool {
+	return b[c/64]&(1<<(c%64)) != 0
+}
+
+func (b *byteSet) invert() {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	alternation = concat { "|" concat }
+//	concat      = { repeat }
+//	repeat      = atom [ ( "*" | "+" | "?" ) [ "?" ] ]
+//	atom        = "(" [ "?:" ] alternation ")" | "[" class "]" | "." | "^" | "$" | escape | byte
+type parser struct {
+	expr     string
+	pos      int
+	captures int
+}
+
+func parse(expr string) (*node, int, error) {
+	p := &parser{expr: expr}
+	tree, err := p.alternation()
+	if err != nil {
+		return nil, 0, err
+	}
+	if p.pos < len(expr) {
+		// Only an unbalanced ) stops the top level alternation early.
+		return nil, 0, p.errorf("unexpected )")
+	}
+	return tree, p.captures, nil
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s at offset %d in %q", ErrSyntax, fmt.Sprintf(format, args...), p.pos, p.expr)
+}
+
+func (p *parser) more() bool {
+	return p.pos < len(p.expr)
+}
+
+func (p *parser) peek() byte {
+	return p.expr[p.pos]
+}
+
+func (p *parser) alternation() (*node, error) {
+	first, err := p.concat()
+	if err != nil {
+		return nil, err
+	}
+	alternatives := []*node{first}
+	for p.more() && p.peek() == '|' {
+		p.pos++
+		next, err := p.concat()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, next)
+	}
+	if len(alternatives) == 1 {
+		return first, nil
+	}
+	return &node{kind: nodeAlt, children: alternatives}, nil
+}
+
+func (p *parser) concat() (*node, error) {
+	var items []*node
+	for p.more() && p.peek() != '|' && p.peek() != ')' {
+		item, err := p.repeat()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	switch len(items) {
+	case 0:
+		return &node{kind: nodeEmpty}, nil
+	case 1:
+		return items[0], nil
+	}
+	return &node{kind: nodeConcat, children: items}, nil
+}
+
+func (p *parser) repeat() (*node, error) {
+	atom, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	if !p.more() {
+		return atom, nil
+	}
+
+	var kind nodeKind
+	switch p.peek() {
+	case '*':
+		kind = nodeStar
+	case '+':
+		kind = nodePlus
+	case '?':
+		kind = nodeQuest
+	default:
+		return atom, nil
+	}
+	p.pos++
+	greedy := true
+	if p.more() && p.peek() == '?' {
+		p.pos++
+		greedy = false
+	}
+	if p.more() && (p.peek() == '*' || p.peek() == '+' || p.peek() == '?') {
+		return nil, p.errorf("nested repetition")
+	}
+	return &node{kind: kind, children: []*node{atom}, greedy: greedy}, nil
+}
+
+func (p *parser) atom() (*node, error) {
+	c := p.peek()
+	switch c {
+	case '*', '+', '?':
+		return nil, p.errorf("missing argument to repetition operator %c", c)
+	case '(':
+		p.pos++
+		capture := true
+		if p.pos+1 < len(p.expr) && p.expr[p.pos] == '?' && p.expr[p.pos+1] == ':' {
+			p.pos += 2
+			capture = false
+		}
+		index := 0
+		if capture {
+			p.captures++
+			index = p.captures
+		}
+		inner, err := p.alternation()
+		if err != nil {
+			return nil, err
+		}
+		if !p.more() {
+			return nil, p.errorf("missing )")
+		}
+		p.pos++
+		if !capture {
+			return inner, nil
+		}
+		return &node{kind: nodeCapture, children: []*node{inner}, index: index}, nil
+	case '[':
+		return p.class()
+	case '.':
+		p.pos++
+		n := &node{kind: nodeSet}
+		n.set.add('\n')
+		n.set.invert()
+		return n, nil
+	case '^':
+		p.pos++
+		return &node{kind: nodeBegin}, nil
+	case '$':
+		p.pos++
+		return &node{kind: nodeEnd}, nil
+	case '\\':
+		set, err := p.escape()
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nodeSet, set: set}, nil
+	}
+	p.pos++
+	n := &node{kind: nodeSet}
+	n.set.add(c)
+	return n, nil
+}
+
+// class parses a bracketed class. A ] right after [ or [^ is a literal, as is a
+// - at either end of the class.
+func (p *parser) class() (*node, error) {
+	p.pos++ // [
+	n := &node{kind: nodeSet}
+	negated := p.more() && p.peek() == '^'
+	if negated {
+		p.pos++
+	}
+
+	first := true
+	for p.more() && (p.peek() != ']' || first) {
+		first = false
+		var lo byte
+		if p.peek() == '\\' {
+			set, single, err := p.classEscape()
+			if err != nil {
+				return nil, err
+			}
+			if set != nil {
+				n.set.addSet(*set)
+				continue
+			}
+			lo = single
+		} else {
+			lo = p.peek()
+			p.pos++
+		}
+
+		if p.pos+1 < len(p.expr) && p.peek() == '-' && p.expr[p.pos+1] != ']' {
+			p.pos++
+			var hi byte
+			if p.peek() == '\\' {
+				set, single, err := p.classEscape()
+				if err != nil {
+					return nil, err
+				}
+				if set != nil {
+					return nil, p.errorf("invalid range end")
+				}
+				hi = single
+			} else {
+				hi = p.peek()
+				p.pos++
+			}
+			if hi < lo {
+				return nil, p.errorf("invalid range %c-%c", lo, hi)
+			}
+			n.set.addRange(lo, hi)
+		} else {
+			n.set.add(lo)
+		}
+	}
+	if !p.more() {
+		return nil, p.errorf("missing ]")
+	}
+	p.pos++ // ]
+	if negated {
+		n.set.invert()
+	}
+	return n, nil
+}
+
+// classEscape parses an escape inside a class. It returns either a set for the
+// Perl classes or a single byte.
+func (p *parser) classEscape() (*byteSet, byte, error) {
+	if p.pos+1 < len(p.expr) {
+		if set, ok := perlClass(p.expr[p.pos+1]); ok {
+			p.pos += 2
+			return &set, 0, nil
+		}
+	}
+	set, err := p.escape()
+	if err != nil {
+		return nil, 0, err
+	}
+	for c := 0; c < 256; c++ {
+		if set.has(byte(c)) {
+			return nil, byte(c), nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// escape parses \d, \w, \s, their negations, the control escapes and escaped
+// punctuation.
+func (p *parser) escape() (byteSet, error) {
+	var set byteSet
+	if p.pos+1 == len(p.expr) {
+		return set, p.errorf("trailing backslash")
+	}
+	c := p.expr[p.pos+1]
+	p.pos += 2
+
+	if perl, ok := perlClass(c); ok {
+		return perl, nil
+	}
+	switch c {
+	case 'n':
+		set.add('\n')
+	case 't':
+		set.add('\t')
+	case 'r':
+		set.add('\r')
+	case 'f':
+		set.add('\f')
+	case 'v':
+		set.add('\v')
+	default:
+		if isWordByte(c) {
+			p.pos -= 2
+			return set, p.errorf("invalid escape \\%c", c)
+		}
+		set.add(c)
+	}
+	return set, nil
+}
+
+// perlClass returns the set of \d, \w, \s and their negations \D, \W, \S.
+func perlClass(c byte) (byteSet, bool) {
+	var set byteSet
+	switch c {
+	case 'd', 'D':
+		set.addRange('0', '9')
+	case 'w', 'W':
+		set.addRange('0', '9')
+		set.addRange('A', 'Z')
+		set.addRange('a', 'z')
+		set.add('_')
+	case 's', 'S':
+		for _, space := range []byte("\t\n\f\r ") {
+			set.add(space)
+		}
+	default:
+		return set, false
+	}
+	if c >= 'A' && c <= 'Z' {
+		set.invert()
+	}
+	return set, true
+}
+
+func isWordByte(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '_'
+}
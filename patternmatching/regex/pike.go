@@ -0,0 +1,111 @@
+package regex
+
+// thread is an NFA state reached by the simulation, with the capture offsets
+// recorded on the way to it.
+type thread struct {
+	pc   int
+	caps []int
+}
+
+// machine simulates the NFA on a text, keeping every active state at once, so
+// every byte is read once and the search takes O(len(program) * len(text)) time
+// whatever the expression. Threads are kept in priority order, which gives the
+// leftmost-first submatches of Perl and Go's regexp package (Pike's VM).
+type machine struct {
+	prog *program
+	text string
+	// visited[pc] is the generation pc was last added in; a state is added at most
+	// once per text position.
+	visited    []int
+	generation int
+	steps      int
+}
+
+func newMachine(prog *program, text string) *machine {
+	return &machine{prog: prog, text: text, visited: make([]int, len(prog.insts))}
+}
+
+// add follows the instructions not consuming input from pc and appends the
+// threads reached, in priority order, to list.
+func (m *machine) add(list []thread, pc, pos int, caps []int) []thread {
+	if m.visited[pc] == m.generation {
+		return list
+	}
+	m.visited[pc] = m.generation
+
+	in := &m.prog.insts[pc]
+	switch in.op {
+	case opJmp:
+		return m.add(list, in.x, pos, caps)
+	case opSplit:
+		list = m.add(list, in.x, pos, caps)
+		return m.add(list, in.y, pos, caps)
+	case opSave:
+		if in.n < len(caps) {
+			// Threads share their offsets until one of them records a new one.
+			caps = append([]int(nil), caps...)
+			caps[in.n] = pos
+		}
+		return m.add(list, pc+1, pos, caps)
+	case opBegin:
+		if pos != 0 {
+			return list
+		}
+		return m.add(list, pc+1, pos, caps)
+	case opEnd:
+		if pos != len(m.text) {
+			return list
+		}
+		return m.add(list, pc+1, pos, caps)
+	}
+	return append(list, thread{pc: pc, caps: caps})
+}
+
+// search returns the capture offsets of the leftmost-first match starting at or
+// after start, or nil. slots is the number of offsets to record; with firstOnly
+// the search stops at the first match found, whatever its priority.
+func (m *machine) search(start, slots int, firstOnly bool) []int {
+	initial := make([]int, slots)
+	for i := range initial {
+		initial[i] = -1
+	}
+
+	var matched []int
+	var clist, nlist []thread
+	m.generation++
+	for pos := start; ; pos++ {
+		if matched == nil {
+			// A new thread per position makes the search unanchored. It has the
+			// lowest priority, so matches starting earlier win.
+			clist = m.add(clist, 0, pos, initial)
+		}
+		if len(clist) == 0 && matched != nil {
+			break
+		}
+
+		m.generation++
+		nlist = nlist[:0]
+		for _, th := range clist {
+			in := &m.prog.insts[th.pc]
+			if in.op == opMatch {
+				matched = th.caps
+				if firstOnly {
+					return matched
+				}
+				// The threads after this one have a lower priority.
+				break
+			}
+			if pos < len(m.text) {
+				m.steps++
+				if in.set.has(m.text[pos]) {
+					nlist = m.add(nlist, th.pc+1, pos+1, th.caps)
+				}
+			}
+		}
+		clist, nlist = nlist, clist
+		if pos == len(m.text) {
+			break
+		}
+	}
+	return matched
+}
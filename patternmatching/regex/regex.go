@@ -0,0 +1,150 @@
+// Package regex is a small regular expression engine built on Thompson's NFA
+// construction.
+//
+// The syntax is a subset of Go's regexp package working on bytes:
+//
+//	xy       x followed by y
+//	x|y      x or y, x preferred
+//	x* x+ x? zero or more, one or more, zero or one x; greedy
+//	x*? x+? x?? the same, preferring fewer x
+//	(x)      capture group; (?:x) groups without capturing
+//	[a-z]    one byte of the class; [^a-z] one byte not in it
+//	.        any byte except newline
+//	^ $      beginning and end of the text
+//	\d \w \s digits, word bytes, white space; \D \W \S their complements
+//
+// The NFA is simulated with every active state at once, so a search reads every
+// byte once and never backtracks: it takes O(m*n) time for an expression compiled
+// to m instructions, where a backtracking engine may take exponential time.
+// MatchDFA builds a DFA from the NFA lazily, one state per set of NFA states
+// actually reached, and then needs a single table lookup per byte.
+//
+// Every search reports Stats, whose steps are comparable to the character
+// comparisons of the literal matchers in the patternmatching package.
+package regex
+
+import (
+	"errors"
+)
+
+// ErrSyntax is returned by Compile for malformed expressions.
+var ErrSyntax = errors.New("regex: syntax error")
+
+// Stats describes the work done by a single search.
+type Stats struct {
+	// Steps counts the bytes tested by NFA states for the NFA simulation, and the
+	// transitions taken for the DFA.
+	Steps int
+	// States is the number of DFA states built during the search. States built by
+	// earlier searches are reused and not counted again.
+	States int
+}
+
+func (st *Stats) add(other Stats) {
+	st.Steps += other.Steps
+	st.States += other.States
+}
+
+// Regexp is a compiled regular expression. It is safe for concurrent use.
+type Regexp struct {
+	expr   string
+	prog   *program
+	groups int
+	// dfa is built lazily by MatchDFA and shared by all its callers.
+	dfa *dfa
+}
+
+// Compile parses expr and compiles it to an NFA.
+func Compile(expr string) (*Regexp, error) {
+	tree, groups, err := parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	prog := compile(tree, groups)
+	return &Regexp{expr: expr, prog: prog, groups: groups, dfa: newDFA(prog)}, nil
+}
+
+// MustCompile is like Compile but panics if the expression cannot be compiled.
+func MustCompile(expr string) *Regexp {
+	re, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// String returns the expression the Regexp was compiled from.
+func (re *Regexp) String() string {
+	return re.expr
+}
+
+// NumSubexp returns the number of capture groups.
+func (re *Regexp) NumSubexp() int {
+	return re.groups
+}
+
+// Match reports whether the expression matches somewhere in text, simulating
+// the NFA. The search stops at the first match found.
+func (re *Regexp) Match(text string) (bool, Stats) {
+	m := newMachine(re.prog, text)
+	matched := m.search(0, 0, true) != nil
+	return matched, Stats{Steps: m.steps}
+}
+
+// MatchDFA reports whether the expression matches somewhere in text, like Match,
+// with the lazily built DFA. The DFA is kept by the Regexp, so later searches
+// reuse the states built by earlier ones. Concurrent searches share the DFA and
+// only wait for each other while a state is being built.
+func (re *Regexp) MatchDFA(text string) (bool, Stats) {
+	var stats Stats
+	matched := re.dfa.match(text, &stats)
+	return matched, stats
+}
+
+// FindIndex returns the offsets [start, end] of the leftmost match, or nil.
+func (re *Regexp) FindIndex(text string) ([]int, Stats) {
+	m := newMachine(re.prog, text)
+	loc := m.search(0, 2, false)
+	return loc, Stats{Steps: m.steps}
+}
+
+// FindSubmatchIndex returns the offsets of the leftmost match followed by the
+// offsets of every capture group, or nil. Groups which did not take part in the
+// match have offsets -1.
+func (re *Regexp) FindSubmatchIndex(text string) ([]int, Stats) {
+	m := newMachine(re.prog, text)
+	loc := m.search(0, re.prog.slots, false)
+	return loc, Stats{Steps: m.steps}
+}
+
+// FindAllIndex returns the offsets of all successive, non-overlapping matches.
+// As in Go's regexp package, an empty match right after a previous match is
+// skipped.
+func (re *Regexp) FindAllIndex(text string) ([][]int, Stats) {
+	var matches [][]int
+	prevEnd := -1
+	// The searches for the successive matches share one machine.
+	m := newMachine(re.prog, text)
+	for pos := 0; pos <= len(text); {
+		loc := m.search(pos, 2, false)
+		if loc == nil {
+			break
+		}
+
+		accept := true
+		if loc[1] == loc[0] {
+			if loc[0] == prevEnd {
+				accept = false
+			}
+			// An empty match cannot move the search forward by itself.
+			pos = loc[1] + 1
+		} else {
+			pos = loc[1]
+		}
+		prevEnd = loc[1]
+		if accept {
+			matches = append(matches, loc)
+		}
+	}
+	return matches, Stats{Steps: m.steps}
+}
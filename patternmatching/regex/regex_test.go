@@ -0,0 +1,128 @@
+package regex
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFindSubmatchIndex(t *testing.T) {
+	tests := []struct {
+		expr     string
+		text     string
+		expected []int
+	}{
+		{expr: "p([a-z]+)ch", text: "peach punch", expected: []int{0, 5, 1, 3}},
+		{expr: "a|ab", text: "xab", expected: []int{1, 2}},
+		{expr: "ab|a", text: "xab", expected: []int{1, 3}},
+		{expr: "(a+)(b*)", text: "caaab", expected: []int{1, 5, 1, 4, 4, 5}},
+		{expr: "(a+?)(a*)", text: "aaa", expected: []int{0, 3, 0, 1, 1, 3}},
+		{expr: "(x)?y", text: "y", expected: []int{0, 1, -1, -1}},
+		{expr: "^abc$", text: "abc", expected: []int{0, 3}},
+		{expr: "^abc$", text: "abcd", expected: nil},
+		{expr: "c$", text: "abcc", expected: []int{3, 4}},
+		{expr: `\d+\.\d+`, text: "pi is 3.14", expected: []int{6, 10}},
+		{expr: `[^\s]+`, text: "  word ", expected: []int{2, 6}},
+		{expr: "(?:ab)+", text: "ababa", expected: []int{0, 4}},
+		{expr: "a.c", text: "a\nc abc", expected: []int{4, 7}},
+		{expr: "[]a]+", text: "x]a]", expected: []int{1, 4}},
+		{expr: "", text: "abc", expected: []int{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			re := MustCompile(tt.expr)
+			loc, _ := re.FindSubmatchIndex(tt.text)
+			if !reflect.DeepEqual(loc, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, loc)
+			}
+			for name, match := range map[string]func(string) (bool, Stats){"NFA": re.Match, "DFA": re.MatchDFA} {
+				if matched, _ := match(tt.text); matched != (tt.expected != nil) {
+					t.Errorf("%s: expected match %v, got %v", name, tt.expected != nil, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{"(a", "a)", "[ab", "*a", "a**", `a\`, "[z-a]", `\q`} {
+		if _, err := Compile(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected ErrSyntax, got %v", expr, err)
+		}
+	}
+}
+
+// randomExpr builds a random expression over the bytes of alphabet.
+func randomExpr(random *rand.Rand, alphabet string, depth int) string {
+	if depth == 0 {
+		return string(alphabet[random.Intn(len(alphabet))])
+	}
+	switch random.Intn(10) {
+	case 0:
+		return randomExpr(random, alphabet, depth-1) + "|" + randomExpr(random, alphabet, depth-1)
+	case 1:
+		return "(" + randomExpr(random, alphabet, depth-1) + ")"
+	case 2:
+		return "(" + randomExpr(random, alphabet, depth-1) + ")*"
+	case 3:
+		return "(" + randomExpr(random, alphabet, depth-1) + ")+?"
+	case 4:
+		return "(?:" + randomExpr(random, alphabet, depth-1) + ")?"
+	case 5:
+		return "[" + alphabet[:1+random.Intn(len(alphabet))] + "]"
+	case 6:
+		return []string{"^", "$", "."}[random.Intn(3)]
+	default:
+		return randomExpr(random, alphabet, depth-1) + randomExpr(random, alphabet, depth-1)
+	}
+}
+
+func TestMatchesGoRegexp(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 2000; round++ {
+		expr := randomExpr(random, "ab", 1+random.Intn(4))
+		var text strings.Builder
+		for i := random.Intn(20); i > 0; i-- {
+			text.WriteByte("abc"[random.Intn(3)])
+		}
+
+		re := MustCompile(expr)
+		oracle := regexp.MustCompile(expr)
+		s := text.String()
+
+		if loc, _ := re.FindSubmatchIndex(s); !reflect.DeepEqual(loc, oracle.FindStringSubmatchIndex(s)) {
+			t.Fatalf("%q in %q: expected submatches %v, got %v", expr, s, oracle.FindStringSubmatchIndex(s), loc)
+		}
+		if all, _ := re.FindAllIndex(s); !reflect.DeepEqual(all, oracle.FindAllStringIndex(s, -1)) {
+			t.Fatalf("%q in %q: expected all matches %v, got %v", expr, s, oracle.FindAllStringIndex(s, -1), all)
+		}
+		expected := oracle.MatchString(s)
+		if matched, _ := re.Match(s); matched != expected {
+			t.Fatalf("%q in %q: NFA match %v, expected %v", expr, s, matched, expected)
+		}
+		if matched, _ := re.MatchDFA(s); matched != expected {
+			t.Fatalf("%q in %q: DFA match %v, expected %v", expr, s, matched, expected)
+		}
+	}
+}
+
+func TestSearchIsLinear(t *testing.T) {
+	// (a*)*b and (a|aa)*c take exponential time with backtracking.
+	for _, expr := range []string{"(a*)*b", "(a|aa)*c"} {
+		re := MustCompile(expr)
+		for _, n := range []int{1000, 2000} {
+			text := strings.Repeat("a", n)
+			_, nfa := re.FindIndex(text)
+			if bound := len(re.prog.insts) * (n + 1); nfa.Steps > bound {
+				t.Errorf("%q on %d bytes: %d NFA steps exceed %d", expr, n, nfa.Steps, bound)
+			}
+			if _, dfa := re.MatchDFA(text); dfa.Steps > n {
+				t.Errorf("%q on %d bytes: %d DFA steps exceed %d", expr, n, dfa.Steps, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package patternmatching
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSliceUnsupported is returned by CompileSlice for algorithms without a
+// generic version.
+var ErrSliceUnsupported = errors.New("patternmatching: algorithm cannot search slices")
+
+// SliceMatcher searches a slice of comparable values, such as tokens, events or
+// enum-encoded symbols, for a pattern compiled ahead of time. It has the methods
+// of Matcher with []T in place of string; the offsets of a Match are indices
+// into the slice. Like a Matcher, it may be shared by many goroutines.
+type SliceMatcher[T comparable] interface {
+	// Pattern returns the pattern the matcher was compiled from.
+	Pattern() []T
+	// Algorithm returns the algorithm used by the matcher.
+	Algorithm() Algorithm
+	// Options returns the matching modes the matcher was compiled with.
+	Options() Options
+	// FindAll returns every match in text, in order of their start offsets.
+	FindAll(text []T) ([]Match, MatchStats)
+	// FindFirst returns the leftmost match. The search stops as soon as it is found.
+	FindFirst(text []T) (Match, bool, MatchStats)
+	// Count returns the number of matches in text.
+	Count(text []T) (int, MatchStats)
+	// FindIter calls yield for every match until yield returns false.
+	FindIter(text []T, yield func(Match) bool) MatchStats
+}
+
+// CompileSlice prepares pattern for searching slices with MorrisPratt,
+// KnuthMorrisPratt, ZAlgorithm, BoyerMoore or BoyerMooreOptimized. The string
+// matchers of these algorithms run the same generic scans on the bytes of the
+// text, so on []byte the matches and the statistics of the searches are the same;
+// only the tables of Boyer-Moore differ.
+//
+// The alphabet of T is unbounded, so the Boyer-Moore bad character table is a
+// map holding the values of the pattern only. Of the options only WithOverlap
+// applies.
+func CompileSlice[T comparable](pattern []T, algo Algorithm, opts ...Option) (SliceMatcher[T], error) {
+	if _, ok := algorithms[algo]; !ok {
+		return nil, fmt.Errorf("patternmatching: unknown algorithm %q", algo)
+	}
+	if len(pattern) == 0 {
+		return nil, ErrEmptyPattern
+	}
+	options, err := byteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	overlap := options.Overlap == Overlapping
+
+	pattern = append([]T(nil), pattern...)
+	var s sliceScanner[T]
+	switch algo {
+	case MorrisPratt, KnuthMorrisPratt:
+		p := newPrefixSearch(pattern)
+		p.resetOnMatch = algo == MorrisPratt && !overlap
+		s = slicePrefixScanner[T]{p}
+	case ZAlgorithm:
+		s = sliceZAlgorithm[T]{newZSearch(pattern)}
+	case BoyerMoore:
+		s = newSliceBoyerMoore(pattern, false, overlap)
+	case BoyerMooreOptimized:
+		s = newSliceBoyerMoore(pattern, true, false)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrSliceUnsupported, algo)
+	}
+	return &sliceMatcher[T]{pattern: pattern, algo: algo, opts: options, scanner: s}, nil
+}
+
+// MustCompileSlice is like CompileSlice but panics if the pattern cannot be compiled.
+func MustCompileSlice[T comparable](pattern []T, algo Algorithm, opts ...Option) SliceMatcher[T] {
+	m, err := CompileSlice(pattern, algo, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// sliceScanner is the generic counterpart of scanner.
+type sliceScanner[T comparable] interface {
+	scan(text []T, s *search)
+	tables() preprocessing
+}
+
+type sliceMatcher[T comparable] struct {
+	pattern []T
+	algo    Algorithm
+	opts    Options
+	scanner sliceScanner[T]
+}
+
+func (m *sliceMatcher[T]) Pattern() []T {
+	return append([]T(nil), m.pattern...)
+}
+
+func (m *sliceMatcher[T]) Algorithm() Algorithm {
+	return m.algo
+}
+
+func (m *sliceMatcher[T]) Options() Options {
+	return m.opts
+}
+
+func (m *sliceMatcher[T]) FindIter(text []T, yield func(Match) bool) MatchStats {
+	if m.opts.Overlap == NonOverlapping {
+		yield = nonOverlapping(yield)
+	}
+	s := search{stats: m.scanner.tables().stats(), yield: func(start int) bool {
+		return yield(Match{Start: start, End: start + len(m.pattern)})
+	}}
+	m.scanner.scan(text, &s)
+	return s.stats
+}
+
+func (m *sliceMatcher[T]) FindAll(text []T) ([]Match, MatchStats) {
+	var matches []Match
+	stats := m.FindIter(text, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	return matches, stats
+}
+
+func (m *sliceMatcher[T]) FindFirst(text []T) (Match, bool, MatchStats) {
+	var first Match
+	found := false
+	stats := m.FindIter(text, func(match Match) bool {
+		first, found = match, true
+		return false
+	})
+	return first, found, stats
+}
+
+func (m *sliceMatcher[T]) Count(text []T) (int, MatchStats) {
+	count := 0
+	stats := m.FindIter(text, func(Match) bool {
+		count++
+		return true
+	})
+	return count, stats
+}
+
+// slicePrefixScanner is prefixScanner over slices.
+type slicePrefixScanner[T comparable] struct {
+	prefixSearch[T]
+}
+
+func (p slicePrefixScanner[T]) scan(text []T, s *search) {
+	p.resume(text, 0, s)
+}
+
+// sliceZAlgorithm is zAlgorithm over slices.
+type sliceZAlgorithm[T comparable] struct {
+	zSearch[T]
+}
+
+func (za sliceZAlgorithm[T]) scan(text []T, s *search) {
+	za.find(text, s)
+}
+
+// sliceBoyerMoore is boyerMoore, or boyerMooreOptimized when it has a good suffix
+// table, over slices.
+type sliceBoyerMoore[T comparable] struct {
+	boyerMooreSearch[T, valueTable[T]]
+}
+
+func newSliceBoyerMoore[T comparable](pattern []T, optimized, overlap bool) sliceBoyerMoore[T] {
+	// Values missing from the pattern count as position 0 without the good suffix
+	// rule, as in boyerMoore.
+	badChar := valueTable[T]{positions: buildSliceBadCharTable(pattern)}
+	if optimized {
+		badChar.absent = -1
+	}
+	// Every entry of the map is estimated at two ints, the value and its position.
+	tables := preprocessing{cost: len(pattern), memory: len(badChar.positions) * 2 * intBytes}
+	var goodSuffix []int
+	if optimized {
+		var cost int
+		goodSuffix, cost = buildGoodSuffixTable(pattern)
+		tables.cost += cost
+		tables.memory += len(goodSuffix) * intBytes
+	}
+	return sliceBoyerMoore[T]{boyerMooreSearch[T, valueTable[T]]{
+		pattern:       pattern,
+		badChar:       badChar,
+		goodSuffix:    goodSuffix,
+		overlap:       overlap,
+		preprocessing: tables,
+	}}
+}
+
+func (b sliceBoyerMoore[T]) scan(text []T, s *search) {
+	b.scanFrom(text, 0, s)
+}
+
+// valueTable is the bad character table of a slice pattern. The alphabet of T is
+// unbounded, so it maps the values of the pattern only, and the others to absent.
+type valueTable[T comparable] struct {
+	positions map[T]int
+	absent    int
+}
+
+func (t valueTable[T]) last(c T) int {
+	if i, ok := t.positions[c]; ok {
+		return i
+	}
+	return t.absent
+}
+
+func buildSliceBadCharTable[T comparable](pattern []T) map[T]int {
+	table := make(map[T]int, len(pattern))
+	for i, c := range pattern {
+		table[c] = i
+	}
+	return table
+}
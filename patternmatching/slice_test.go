@@ -0,0 +1,74 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+var sliceAlgorithms = []Algorithm{MorrisPratt, KnuthMorrisPratt, ZAlgorithm, BoyerMoore, BoyerMooreOptimized}
+
+func TestSliceMatcherAgreesWithStringMatcher(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, algo := range sliceAlgorithms {
+		for round := 0; round < 200; round++ {
+			text := randomText(random, random.Intn(300), "abc")
+			pattern := randomText(random, 1+random.Intn(8), "abc")
+
+			expected, expectedStats := MustCompile(pattern, algo).FindAll(text)
+			matches, stats := MustCompileSlice([]byte(pattern), algo).FindAll([]byte(text))
+			if algo == BoyerMoore || algo == BoyerMooreOptimized {
+				// The bad character table is a map instead of an array.
+				stats.PreprocessingCost, stats.TableMemory = expectedStats.PreprocessingCost, expectedStats.TableMemory
+			}
+			if !reflect.DeepEqual(matches, expected) || stats != expectedStats {
+				t.Fatalf("%s: %q in %q: expected %v %+v, got %v %+v", algo, pattern, text, expected, expectedStats, matches, stats)
+			}
+		}
+	}
+}
+
+type nucleotide uint8
+
+const (
+	adenine nucleotide = iota
+	cytosine
+	guanine
+	thymine
+)
+
+func TestSliceMatcherOnOtherTypes(t *testing.T) {
+	tokens := []string{"if", "x", "==", "nil", "{", "return", "err", "}", "if", "y", "==", "nil", "{", "return", "err", "}"}
+	events := []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 1, 4, 1, 5}
+	dna := []nucleotide{guanine, adenine, thymine, thymine, adenine, cytosine, adenine, thymine, thymine, adenine}
+
+	for _, algo := range sliceAlgorithms {
+		t.Run(string(algo), func(t *testing.T) {
+			count, _ := MustCompileSlice([]string{"==", "nil", "{", "return", "err"}, algo).Count(tokens)
+			if count != 2 {
+				t.Errorf("tokens: expected 2 matches, got %d", count)
+			}
+			first, found, _ := MustCompileSlice([]int{1, 4, 1, 5}, algo).FindFirst(events)
+			if !found || first != (Match{Start: 1, End: 5}) {
+				t.Errorf("events: expected {1 5}, got %v %v", first, found)
+			}
+			matches, _ := MustCompileSlice([]nucleotide{adenine, thymine, thymine, adenine}, algo).FindAll(dna)
+			if !reflect.DeepEqual(matches, []Match{{Start: 1, End: 5}, {Start: 6, End: 10}}) {
+				t.Errorf("dna: unexpected matches %v", matches)
+			}
+		})
+	}
+}
+
+func TestCompileSliceErrors(t *testing.T) {
+	if _, err := CompileSlice([]int{}, KnuthMorrisPratt); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+	if _, err := CompileSlice([]int{1}, KarpRabin); !errors.Is(err, ErrSliceUnsupported) {
+		t.Errorf("expected ErrSliceUnsupported, got %v", err)
+	}
+	if _, err := CompileSlice([]int{1}, "unknown"); err == nil {
+		t.Error("expected an error for an unknown algorithm")
+	}
+}
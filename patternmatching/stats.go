@@ -0,0 +1,81 @@
+package patternmatching
+
+import (
+	"strconv"
+)
+
+// MatchStats describes the work done by a single search.
+//
+// Comparisons, Shifts, ShiftDistance, HashComputations and SpuriousHits count
+// the work of the search itself and are summed when searches are combined.
+// PreprocessingCost and TableMemory describe the tables built from the pattern
+// before any search; every search reports those of its matcher, and the
+// searches of one matcher share them, so Add keeps the largest while
+// AddDistinct sums them.
+type MatchStats struct {
+	// Comparisons counts the character comparisons, or the hash comparisons of
+	// Karp-Rabin and the transition lookups of Aho-Corasick.
+	Comparisons int
+	// Shifts counts the moves of the pattern to a new alignment, as recorded by
+	// the shift events of a trace, and ShiftDistance adds up the positions moved.
+	// Following a failure link of Aho-Corasick moves the pattern by the depth it
+	// drops.
+	Shifts        int
+	ShiftDistance int
+	// HashComputations counts the window hashes computed from scratch or rolled
+	// (Karp-Rabin only).
+	HashComputations int
+	// SpuriousHits counts the windows whose hash equals the pattern hash although
+	// the text differs from the pattern (Karp-Rabin only).
+	SpuriousHits int
+	// PreprocessingCost counts the steps building the pattern tables: character
+	// comparisons, table entries written and hash updates.
+	PreprocessingCost int
+	// TableMemory is the size of the pattern tables in bytes, 8 per int on 64-bit
+	// platforms. Small fixed fields of the matchers are not counted.
+	TableMemory int
+}
+
+// Add accumulates the statistics of another search of the same matcher into
+// st: the counters are summed and the tables, which the searches share, are
+// kept once.
+func (st *MatchStats) Add(other MatchStats) {
+	st.addCounters(other)
+	st.PreprocessingCost = max(st.PreprocessingCost, other.PreprocessingCost)
+	st.TableMemory = max(st.TableMemory, other.TableMemory)
+}
+
+// AddDistinct accumulates the statistics of a search of another matcher into
+// st, summing its tables as well.
+func (st *MatchStats) AddDistinct(other MatchStats) {
+	st.addCounters(other)
+	st.PreprocessingCost += other.PreprocessingCost
+	st.TableMemory += other.TableMemory
+}
+
+func (st *MatchStats) addCounters(other MatchStats) {
+	st.Comparisons += other.Comparisons
+	st.Shifts += other.Shifts
+	st.ShiftDistance += other.ShiftDistance
+	st.HashComputations += other.HashComputations
+	st.SpuriousHits += other.SpuriousHits
+}
+
+// intBytes is the size of an int.
+const intBytes = strconv.IntSize / 8
+
+// preprocessing is what building the tables of a pattern cost. The scanners
+// embed it, which gives them the tables method.
+type preprocessing struct {
+	cost   int
+	memory int
+}
+
+func (p preprocessing) tables() preprocessing {
+	return p
+}
+
+// stats returns the statistics a search starts from.
+func (p preprocessing) stats() MatchStats {
+	return MatchStats{PreprocessingCost: p.cost, TableMemory: p.memory}
+}
@@ -0,0 +1,119 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMatchStatsOfEveryAlgorithm(t *testing.T) {
+	text := "THIS TEST WILL HAVE MULTIPLE MATCHES, SO THAT WE CAN TEST. ONE MORE TEST."
+	// The comparisons are those of text_pattern_matching_test.go. The bad character
+	// tables are written once for every byte, then once for every pattern byte.
+	tests := []struct {
+		algo     Algorithm
+		expected MatchStats
+	}{
+		{BruteForce, MatchStats{Comparisons: 86, Shifts: 70, ShiftDistance: 70}},
+		{MorrisPratt, MatchStats{Comparisons: 78, Shifts: 64, ShiftDistance: 73, PreprocessingCost: 3, TableMemory: 4 * intBytes}},
+		{KnuthMorrisPratt, MatchStats{Comparisons: 81, Shifts: 67, ShiftDistance: 73, PreprocessingCost: 3, TableMemory: 4 * intBytes}},
+		{KarpRabin, MatchStats{Comparisons: 70, Shifts: 70, ShiftDistance: 70, HashComputations: 70, SpuriousHits: 4, PreprocessingCost: 7}},
+		{KarpRabin64, MatchStats{Comparisons: 70, Shifts: 70, ShiftDistance: 70, HashComputations: 70, PreprocessingCost: 8}},
+		{BoyerMoore, MatchStats{Comparisons: 42, Shifts: 27, ShiftDistance: 72, PreprocessingCost: 260, TableMemory: 256 * intBytes}},
+		{BoyerMooreOptimized, MatchStats{Comparisons: 33, Shifts: 21, ShiftDistance: 71, PreprocessingCost: 268, TableMemory: 261 * intBytes}},
+		{Horspool, MatchStats{Comparisons: 33, Shifts: 21, ShiftDistance: 71, PreprocessingCost: 259, TableMemory: 256 * intBytes}},
+		{Sunday, MatchStats{Comparisons: 26, Shifts: 16, ShiftDistance: 73, PreprocessingCost: 260, TableMemory: 256 * intBytes}},
+		// A trie of 5 nodes with 4 transitions and 1 pattern.
+		{AhoCorasick, MatchStats{Comparisons: 84, Shifts: 67, ShiftDistance: 73, PreprocessingCost: 7, TableMemory: 5*3*intBytes + 4*(1+intBytes) + intBytes}},
+		{ZAlgorithm, MatchStats{Comparisons: 79, Shifts: 70, ShiftDistance: 70, PreprocessingCost: 3, TableMemory: 4 * intBytes}},
+		{TwoWay, MatchStats{Comparisons: 74, Shifts: 58, ShiftDistance: 71, PreprocessingCost: 7}},
+	}
+	for _, tt := range tests {
+		count, stats := MustCompile("TEST", tt.algo).Count(text)
+		if count != 3 || stats != tt.expected {
+			t.Errorf("%s: expected 3 matches and %+v, got %d and %+v", tt.algo, tt.expected, count, stats)
+		}
+	}
+}
+
+func TestShiftsMatchTrace(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		text := randomText(random, random.Intn(100), "ab")
+		pattern := randomText(random, 1+random.Intn(6), "ab")
+		for _, algo := range Algorithms() {
+			trace, err := TraceSearch(MustCompile(pattern, algo), text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			shifts, distance := 0, 0
+			for _, event := range trace.Events {
+				if event.Kind == EventShift {
+					shifts++
+					distance += event.Shift
+				}
+			}
+			if trace.Stats.Shifts != shifts || trace.Stats.ShiftDistance != distance {
+				t.Fatalf("%s, %q in %q: %d shifts by %d, trace has %d by %d",
+					algo, pattern, text, trace.Stats.Shifts, trace.Stats.ShiftDistance, shifts, distance)
+			}
+		}
+	}
+}
+
+func TestPreprocessingIsReportedOnce(t *testing.T) {
+	m := MustCompile("ABCAB", BoyerMooreOptimized)
+	_, empty := m.FindAll("")
+	if empty.PreprocessingCost == 0 || empty.TableMemory == 0 || empty.Comparisons != 0 {
+		t.Fatalf("an empty search should report only the tables, got %+v", empty)
+	}
+
+	text := "ABCABCABCAB"
+	_, whole := m.FindAll(text)
+	var combined MatchStats
+	for _, piece := range []string{text[:6], text[6:]} {
+		_, stats := m.FindAll(piece)
+		combined.Add(stats)
+	}
+	if combined.PreprocessingCost != whole.PreprocessingCost || combined.TableMemory != whole.TableMemory {
+		t.Errorf("combined searches should keep the tables once, got %+v and %+v", combined, whole)
+	}
+}
+
+func TestMatchStatsAdd(t *testing.T) {
+	first := MatchStats{Comparisons: 1, Shifts: 2, ShiftDistance: 3, HashComputations: 4, SpuriousHits: 5, PreprocessingCost: 6, TableMemory: 7}
+	second := MatchStats{Comparisons: 10, Shifts: 20, ShiftDistance: 30, HashComputations: 40, SpuriousHits: 50, PreprocessingCost: 4, TableMemory: 9}
+
+	same := first
+	same.Add(second)
+	if expected := (MatchStats{11, 22, 33, 44, 55, 6, 9}); same != expected {
+		t.Errorf("Add: expected %+v, got %+v", expected, same)
+	}
+	distinct := first
+	distinct.AddDistinct(second)
+	if expected := (MatchStats{11, 22, 33, 44, 55, 10, 16}); distinct != expected {
+		t.Errorf("AddDistinct: expected %+v, got %+v", expected, distinct)
+	}
+}
+
+func TestDictionaryAndMultiKarpRabinStats(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers"}
+	dictionary, err := NewDictionary(patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, stats := dictionary.FindAll("ushers")
+	if stats.Comparisons != 7 || stats.PreprocessingCost == 0 || stats.TableMemory == 0 {
+		t.Errorf("unexpected dictionary statistics %+v", stats)
+	}
+
+	mk, err := NewMultiKarpRabin(patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := "ushers"
+	_, stats = mk.FindAll(text)
+	// One rolling hash per distinct length (2, 3 and 4) is updated at every byte.
+	if stats.HashComputations != 3*len(text) || stats.TableMemory != len(patterns)*(8+intBytes) {
+		t.Errorf("unexpected Karp-Rabin statistics %+v", stats)
+	}
+}
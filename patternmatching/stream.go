@@ -0,0 +1,190 @@
+package patternmatching
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultChunkSize is the number of bytes a StreamSearcher reads at once by default.
+const DefaultChunkSize = 64 * 1024
+
+// ErrStreamingUnsupported is returned by NewStreamSearcher for algorithms which
+// cannot search a stream piece by piece.
+var ErrStreamingUnsupported = errors.New("patternmatching: algorithm does not support streaming")
+
+// StreamSearcher finds a pattern in data read from an io.Reader, holding only one
+// chunk in memory at a time. Matches straddling two chunks are found as well, and
+// the matches and comparisons are exactly those of searching the whole input at once.
+//
+// Knuth-Morris-Pratt (and Morris-Pratt) carry the number of matched pattern
+// characters over to the next chunk. Boyer-Moore carries over the unscanned tail of
+// the chunk, which is shorter than the pattern, and its next alignment.
+type StreamSearcher struct {
+	chunkSize int
+	streamer  streamer
+}
+
+// streamer is implemented by scanners which can search a stream piece by piece.
+type streamer interface {
+	scanner
+	newStream() stream
+}
+
+// stream holds the state of one scan carried between chunks. feed scans the next
+// chunk, reporting absolute match offsets, and tells whether the scan should go on.
+type stream interface {
+	feed(chunk string, s *search) bool
+}
+
+// NewStreamSearcher prepares pattern for searching streams with the given algorithm.
+// A chunkSize of 0 or less selects DefaultChunkSize.
+func NewStreamSearcher(pattern string, algo Algorithm, chunkSize int) (*StreamSearcher, error) {
+	m, err := Compile(pattern, algo)
+	if err != nil {
+		return nil, err
+	}
+	st, ok := m.(*matcher).scanner.(streamer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStreamingUnsupported, algo)
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &StreamSearcher{chunkSize: chunkSize, streamer: st}, nil
+}
+
+// Search reads r until EOF and calls fn with the absolute byte offset of every match.
+// It stops early when fn returns an error, reading fails or ctx is cancelled, and
+// returns that error together with the statistics collected so far.
+func (st *StreamSearcher) Search(ctx context.Context, r io.Reader, fn func(offset int64) error) (MatchStats, error) {
+	var fnErr error
+	s := search{stats: st.streamer.tables().stats(), yield: func(start int) bool {
+		fnErr = fn(int64(start))
+		return fnErr == nil
+	}}
+	state := st.streamer.newStream()
+	chunk := make([]byte, st.chunkSize)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return s.stats, err
+		}
+		n, err := r.Read(chunk)
+		if n > 0 && !state.feed(string(chunk[:n]), &s) {
+			return s.stats, fnErr
+		}
+		if errors.Is(err, io.EOF) {
+			return s.stats, nil
+		}
+		if err != nil {
+			return s.stats, err
+		}
+	}
+}
+
+// Matches searches r in a new goroutine and sends the absolute byte offset of every
+// match on the returned channel, which is closed when the search ends. The error
+// channel then receives the error which ended the search, if any, and is closed.
+func (st *StreamSearcher) Matches(ctx context.Context, r io.Reader) (<-chan int64, <-chan error) {
+	offsets := make(chan int64)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(offsets)
+
+		_, err := st.Search(ctx, r, func(offset int64) error {
+			select {
+			case offsets <- offset:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return offsets, errs
+}
+
+// prefixStream continues a Knuth-Morris-Pratt scan across chunks.
+type prefixStream struct {
+	scanner prefixScanner
+	// offset is the absolute offset of the next chunk.
+	offset int64
+	// matched is the number of pattern characters matched at the end of the previous chunk.
+	matched int
+}
+
+func (p prefixScanner) newStream() stream {
+	return &prefixStream{scanner: p}
+}
+
+func (p *prefixStream) feed(chunk string, s *search) bool {
+	piece := withOffset(s, p.offset)
+	matched, ok := p.scanner.resume([]byte(chunk), p.matched, piece)
+	s.stats.Add(piece.stats)
+
+	p.matched = matched
+	p.offset += int64(len(chunk))
+	return ok
+}
+
+// shiftScanner is implemented by the Boyer-Moore variants, which scan the text
+// alignment by alignment from left to right.
+type shiftScanner interface {
+	scanFrom(text []byte, shift int, s *search) (int, bool)
+}
+
+// windowStream continues a Boyer-Moore scan across chunks.
+type windowStream struct {
+	scanner shiftScanner
+	// pending is the tail of the previous chunks starting at the next alignment.
+	pending string
+	// offset is the absolute offset of pending.
+	offset int64
+	// skip is the number of bytes of the next chunks the next alignment jumps over.
+	skip int
+}
+
+func (b boyerMoore) newStream() stream {
+	return &windowStream{scanner: b}
+}
+
+func (b boyerMooreOptimized) newStream() stream {
+	return &windowStream{scanner: b}
+}
+
+func (w *windowStream) feed(chunk string, s *search) bool {
+	if w.skip >= len(chunk) {
+		w.skip -= len(chunk)
+		return true
+	}
+	text := w.pending + chunk[w.skip:]
+	w.skip = 0
+
+	piece := withOffset(s, w.offset)
+	next, ok := w.scanner.scanFrom([]byte(text), 0, piece)
+	s.stats.Add(piece.stats)
+
+	if next > len(text) {
+		w.skip = next - len(text)
+		w.pending = ""
+	} else {
+		w.pending = text[next:]
+	}
+	w.offset += int64(next)
+	return ok
+}
+
+// withOffset returns a search reporting the matches to s moved by offset.
+// Its statistics start from zero and have to be added to s by the caller.
+func withOffset(s *search, offset int64) *search {
+	return &search{yield: func(start int) bool {
+		return s.yield(int(offset) + start)
+	}}
+}
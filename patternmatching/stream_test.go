@@ -0,0 +1,107 @@
+package patternmatching
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var streamingAlgorithms = []Algorithm{MorrisPratt, KnuthMorrisPratt, BoyerMoore, BoyerMooreOptimized}
+
+func TestStreamSearcherMatchesInMemorySearch(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 30; round++ {
+		text := randomText(random, 1+random.Intn(200), "ab")
+		pattern := randomText(random, 1+random.Intn(5), "ab")
+
+		for _, algo := range streamingAlgorithms {
+			expected, expectedStats := MustCompile(pattern, algo).FindAll(text)
+			for _, chunkSize := range []int{1, 2, 3, 7, 64} {
+				searcher, err := NewStreamSearcher(pattern, algo, chunkSize)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var offsets []int64
+				stats, err := searcher.Search(context.Background(), strings.NewReader(text), func(offset int64) error {
+					offsets = append(offsets, offset)
+					return nil
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var expectedOffsets []int64
+				for _, match := range expected {
+					expectedOffsets = append(expectedOffsets, int64(match.Start))
+				}
+				if !reflect.DeepEqual(offsets, expectedOffsets) || stats != expectedStats {
+					t.Fatalf("%s, chunk size %d, %q in %q: expected %v (%+v), got %v (%+v)",
+						algo, chunkSize, pattern, text, expectedOffsets, expectedStats, offsets, stats)
+				}
+			}
+		}
+	}
+}
+
+func TestStreamSearcherHandlesShortReads(t *testing.T) {
+	searcher, err := NewStreamSearcher("BOUNDARY", BoyerMooreOptimized, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader := iotest.OneByteReader(strings.NewReader("A BOUNDARY STRADDLING BOUNDARY"))
+
+	var offsets []int64
+	matches, errs := searcher.Matches(context.Background(), reader)
+	for offset := range matches {
+		offsets = append(offsets, offset)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(offsets, []int64{2, 22}) {
+		t.Errorf("expected matches at [2 22], got %v", offsets)
+	}
+}
+
+func TestStreamSearcherStopsOnCallbackError(t *testing.T) {
+	searcher, err := NewStreamSearcher("AB", KnuthMorrisPratt, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := errors.New("stop")
+	calls := 0
+	_, err = searcher.Search(context.Background(), strings.NewReader("ABABABAB"), func(int64) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("expected the search to stop after the first match, got %d calls and %v", calls, err)
+	}
+}
+
+func TestStreamSearcherCancellation(t *testing.T) {
+	searcher, err := NewStreamSearcher("AB", BoyerMoore, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	offsets, errs := searcher.Matches(ctx, strings.NewReader(strings.Repeat("AB", 100)))
+	for range offsets {
+	}
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNewStreamSearcherUnsupportedAlgorithm(t *testing.T) {
+	if _, err := NewStreamSearcher("AB", KarpRabin, 0); !errors.Is(err, ErrStreamingUnsupported) {
+		t.Errorf("expected ErrStreamingUnsupported, got %v", err)
+	}
+}
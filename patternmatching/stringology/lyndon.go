@@ -0,0 +1,34 @@
+package stringology
+
+// LyndonFactorization splits s into Lyndon words w1 >= w2 >= ... >= wk with
+// Duval's algorithm. A Lyndon word is strictly smaller than all its proper
+// rotations, e.g. "aab" or "abb"; every string has exactly one such
+// factorization.
+//
+// Duval's algorithm reads s once, keeping a prefix of the form (uv)^k u with uv
+// a Lyndon word, and emits the copies of uv when the next byte is smaller than
+// the one a repetition would continue with. It takes O(n) time.
+func LyndonFactorization(s string) []string {
+	var factors []string
+	for i := 0; i < len(s); {
+		j, k := i+1, i
+		for j < len(s) && s[k] <= s[j] {
+			if s[k] < s[j] {
+				k = i
+			} else {
+				k++
+			}
+			j++
+		}
+		for i <= k {
+			factors = append(factors, s[i:i+j-k])
+			i += j - k
+		}
+	}
+	return factors
+}
+
+// IsLyndon tells whether s is a Lyndon word.
+func IsLyndon(s string) bool {
+	return s != "" && len(LyndonFactorization(s)) == 1
+}
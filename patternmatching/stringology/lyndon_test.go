@@ -0,0 +1,59 @@
+package stringology
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// naiveIsLyndon compares s with its proper rotations.
+func naiveIsLyndon(s string) bool {
+	for k := 1; k < len(s); k++ {
+		if s[k:]+s[:k] <= s {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func TestLyndonFactorization(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"banana", []string{"b", "an", "an", "a"}},
+		{"aab", []string{"aab"}},
+		{"abab", []string{"ab", "ab"}},
+		{"cba", []string{"c", "b", "a"}},
+	}
+	for _, tt := range tests {
+		if factors := LyndonFactorization(tt.s); !reflect.DeepEqual(factors, tt.expected) {
+			t.Errorf("LyndonFactorization(%q) = %q, expected %q", tt.s, factors, tt.expected)
+		}
+	}
+}
+
+func TestLyndonFactorizationProperties(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		s := randomString(random, random.Intn(20), "abc"[:1+random.Intn(3)])
+		factors := LyndonFactorization(s)
+		if strings.Join(factors, "") != s {
+			t.Fatalf("LyndonFactorization(%q) = %q does not spell the string", s, factors)
+		}
+		for j, factor := range factors {
+			if !naiveIsLyndon(factor) {
+				t.Fatalf("LyndonFactorization(%q): %q is not a Lyndon word", s, factor)
+			}
+			if j > 0 && factors[j-1] < factor {
+				t.Fatalf("LyndonFactorization(%q) = %q is not non-increasing", s, factors)
+			}
+		}
+		if IsLyndon(s) != naiveIsLyndon(s) {
+			t.Fatalf("IsLyndon(%q) = %t", s, IsLyndon(s))
+		}
+	}
+}
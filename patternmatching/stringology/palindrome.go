@@ -0,0 +1,58 @@
+package stringology
+
+// PalindromeRadii returns Manacher's table for s: entry c, for the 2n+1 centres
+// of s, is the length of the longest palindrome centred there. Even c are the
+// centres between two bytes (or at the ends), odd c the centres on a byte, so
+// the palindrome of entry c is s[(c-r)/2 : (c+r)/2] with r the entry.
+//
+// Manacher's algorithm visits the centres from left to right and keeps the
+// palindrome reaching furthest right. A centre inside it starts from the radius
+// of its mirror image, so every comparison either extends that palindrome or
+// ends a centre, and the table takes O(n) time.
+func PalindromeRadii(s string) []int {
+	n := 2*len(s) + 1
+	radii := make([]int, n)
+	// at returns the byte at centre c, or -1 between bytes, where both sides of
+	// every palindrome agree.
+	at := func(c int) int {
+		if c%2 == 0 {
+			return -1
+		}
+		return int(s[c/2])
+	}
+	center, right := 0, 0
+	for c := 0; c < n; c++ {
+		r := 0
+		if c < right {
+			r = min(radii[2*center-c], right-c)
+		}
+		for c-r-1 >= 0 && c+r+1 < n && at(c-r-1) == at(c+r+1) {
+			r++
+		}
+		radii[c] = r
+		if c+r > right {
+			center, right = c, c+r
+		}
+	}
+	return radii
+}
+
+// LongestPalindromes returns the length of the longest palindromic substrings of
+// s and the start offsets of all of them, in increasing order.
+func LongestPalindromes(s string) (int, []int) {
+	if s == "" {
+		return 0, nil
+	}
+	radii := PalindromeRadii(s)
+	longest := 0
+	for _, r := range radii {
+		longest = max(longest, r)
+	}
+	var starts []int
+	for c, r := range radii {
+		if r == longest {
+			starts = append(starts, (c-r)/2)
+		}
+	}
+	return longest, starts
+}
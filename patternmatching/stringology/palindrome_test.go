@@ -0,0 +1,75 @@
+package stringology
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func isPalindrome(s string) bool {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		if s[i] != s[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// naiveLongestPalindromes checks every substring.
+func naiveLongestPalindromes(s string) (int, []int) {
+	longest := 0
+	var starts []int
+	for i := 0; i < len(s); i++ {
+		for j := i + 1; j <= len(s); j++ {
+			if !isPalindrome(s[i:j]) {
+				continue
+			}
+			switch {
+			case j-i > longest:
+				longest, starts = j-i, []int{i}
+			case j-i == longest:
+				starts = append(starts, i)
+			}
+		}
+	}
+	return longest, starts
+}
+
+func TestPalindromeRadii(t *testing.T) {
+	// Centres: | a | b | a | a |
+	expected := []int{0, 1, 0, 3, 0, 1, 2, 1, 0}
+	if radii := PalindromeRadii("abaa"); !reflect.DeepEqual(radii, expected) {
+		t.Errorf("PalindromeRadii(abaa) = %v, expected %v", radii, expected)
+	}
+}
+
+func TestLongestPalindromes(t *testing.T) {
+	tests := []struct {
+		s      string
+		length int
+		starts []int
+	}{
+		{"", 0, nil},
+		{"abc", 1, []int{0, 1, 2}},
+		{"forgeeksskeegfor", 10, []int{3}},
+		{"abacdfgdcaba", 3, []int{0, 9}},
+	}
+	for _, tt := range tests {
+		length, starts := LongestPalindromes(tt.s)
+		if length != tt.length || !reflect.DeepEqual(starts, tt.starts) {
+			t.Errorf("LongestPalindromes(%q) = %d, %v, expected %d, %v", tt.s, length, starts, tt.length, tt.starts)
+		}
+	}
+}
+
+func TestLongestPalindromesMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		s := randomString(random, 1+random.Intn(25), "abc"[:1+random.Intn(3)])
+		length, starts := LongestPalindromes(s)
+		expectedLength, expectedStarts := naiveLongestPalindromes(s)
+		if length != expectedLength || !reflect.DeepEqual(starts, expectedStarts) {
+			t.Fatalf("LongestPalindromes(%q) = %d, %v, expected %d, %v", s, length, starts, expectedLength, expectedStarts)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package stringology describes the structure of strings: their periods and
+// borders, palindromes, rotations, Lyndon factorization and runs. Strings are
+// compared byte by byte.
+package stringology
+
+import (
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// Borders returns the lengths of the proper borders of s, the prefixes which are
+// also suffixes, longest first. They are the chain lps[n-1], lps[lps[n-1]-1], ...
+// of the Knuth-Morris-Pratt failure table.
+func Borders(s string) []int {
+	if s == "" {
+		return nil
+	}
+	lps := patternmatching.PrefixFunction(s)
+	var borders []int
+	for b := lps[len(s)-1]; b > 0; b = lps[b-1] {
+		borders = append(borders, b)
+	}
+	return borders
+}
+
+// Period returns the shortest period of s, the smallest p > 0 with s[i] == s[i+p]
+// for every i. A border of length b gives the period len(s) - b, so the longest
+// border gives the shortest period. The empty string has period 0.
+func Period(s string) int {
+	if s == "" {
+		return 0
+	}
+	return len(s) - patternmatching.PrefixFunction(s)[len(s)-1]
+}
+
+// Periods returns every period of s in increasing order, len(s) included.
+func Periods(s string) []int {
+	borders := Borders(s)
+	periods := make([]int, 0, len(borders)+1)
+	for _, b := range borders {
+		periods = append(periods, len(s)-b)
+	}
+	if s != "" {
+		periods = append(periods, len(s))
+	}
+	return periods
+}
+
+// PrimitiveRoot returns the shortest u with s = u^k, and k. A string whose only
+// such u is itself is primitive.
+func PrimitiveRoot(s string) (string, int) {
+	if s == "" {
+		return "", 0
+	}
+	p := Period(s)
+	if len(s)%p != 0 {
+		return s, 1
+	}
+	return s[:p], len(s) / p
+}
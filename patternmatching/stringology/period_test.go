@@ -0,0 +1,101 @@
+package stringology
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func randomString(random *rand.Rand, n int, alphabet string) string {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = alphabet[random.Intn(len(alphabet))]
+	}
+	return string(s)
+}
+
+// naivePeriods tries every shift.
+func naivePeriods(s string) []int {
+	var periods []int
+	for p := 1; p <= len(s); p++ {
+		if s[p:] == s[:len(s)-p] {
+			periods = append(periods, p)
+		}
+	}
+	return periods
+}
+
+func TestPeriod(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"", 0},
+		{"a", 1},
+		{"aaaa", 1},
+		{"abab", 2},
+		{"abaab", 3},
+		{"abcab", 3},
+		{"abcd", 4},
+	}
+	for _, tt := range tests {
+		if p := Period(tt.s); p != tt.expected {
+			t.Errorf("Period(%q) = %d, expected %d", tt.s, p, tt.expected)
+		}
+	}
+}
+
+func TestBorders(t *testing.T) {
+	if borders := Borders("abacaba"); !reflect.DeepEqual(borders, []int{3, 1}) {
+		t.Errorf("Borders(abacaba) = %v, expected [3 1]", borders)
+	}
+	if borders := Borders("abc"); borders != nil {
+		t.Errorf("Borders(abc) = %v, expected none", borders)
+	}
+}
+
+func TestPeriodsMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		s := randomString(random, random.Intn(20), "ab")
+		expected := naivePeriods(s)
+		if periods := Periods(s); !reflect.DeepEqual(periods, expected) && len(expected)+len(periods) > 0 {
+			t.Fatalf("Periods(%q) = %v, expected %v", s, periods, expected)
+		}
+		if s == "" {
+			continue
+		}
+		if p := Period(s); p != expected[0] {
+			t.Fatalf("Period(%q) = %d, expected %d", s, p, expected[0])
+		}
+		for j, b := range Borders(s) {
+			if b != len(s)-expected[j] || s[:b] != s[len(s)-b:] {
+				t.Fatalf("Borders(%q) = %v, not matching the periods %v", s, Borders(s), expected)
+			}
+		}
+	}
+}
+
+func TestPrimitiveRoot(t *testing.T) {
+	tests := []struct {
+		s     string
+		root  string
+		power int
+	}{
+		{"", "", 0},
+		{"abab", "ab", 2},
+		{"aaa", "a", 3},
+		{"abaab", "abaab", 1},
+		{"abcabcabc", "abc", 3},
+	}
+	for _, tt := range tests {
+		root, power := PrimitiveRoot(tt.s)
+		if root != tt.root || power != tt.power {
+			t.Errorf("PrimitiveRoot(%q) = %q, %d, expected %q, %d", tt.s, root, power, tt.root, tt.power)
+		}
+		if strings.Repeat(root, power) != tt.s {
+			t.Errorf("%q^%d != %q", root, power, tt.s)
+		}
+	}
+}
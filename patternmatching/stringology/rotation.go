@@ -0,0 +1,45 @@
+package stringology
+
+// MinimalRotation returns the smallest k such that s[k:] + s[:k] is the
+// lexicographically smallest rotation of s, with Booth's algorithm.
+//
+// Booth's algorithm runs the Knuth-Morris-Pratt failure function over s+s,
+// moving the candidate start k whenever a smaller character shows the current
+// candidate cannot be minimal, in O(n) time.
+func MinimalRotation(s string) int {
+	n := len(s)
+	if n == 0 {
+		return 0
+	}
+	at := func(i int) byte { return s[i%n] }
+	failure := make([]int, 2*n)
+	for i := range failure {
+		failure[i] = -1
+	}
+	k := 0
+	for j := 1; j < 2*n; j++ {
+		c := at(j)
+		i := failure[j-k-1]
+		for i != -1 && c != at(k+i+1) {
+			if c < at(k+i+1) {
+				k = j - i - 1
+			}
+			i = failure[i]
+		}
+		if c != at(k+i+1) {
+			// Here i == -1.
+			if c < at(k) {
+				k = j
+			}
+			failure[j-k] = -1
+		} else {
+			failure[j-k] = i + 1
+		}
+	}
+	// Booth finds a minimal rotation; the smallest start of the same rotation is
+	// k modulo the primitive period of s.
+	if p := Period(s); n%p == 0 {
+		k %= p
+	}
+	return k
+}
@@ -0,0 +1,47 @@
+package stringology
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// naiveMinimalRotation compares every rotation.
+func naiveMinimalRotation(s string) int {
+	best := 0
+	for k := 1; k < len(s); k++ {
+		if s[k:]+s[:k] < s[best:]+s[:best] {
+			best = k
+		}
+	}
+	return best
+}
+
+func TestMinimalRotation(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"bca", 2},
+		{"cabab", 1},
+		{"abab", 0},
+		{"baba", 1},
+		{"bbaaccaadd", 2},
+	}
+	for _, tt := range tests {
+		if k := MinimalRotation(tt.s); k != tt.expected {
+			t.Errorf("MinimalRotation(%q) = %d, expected %d", tt.s, k, tt.expected)
+		}
+	}
+}
+
+func TestMinimalRotationMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		s := randomString(random, 1+random.Intn(16), "abc"[:1+random.Intn(3)])
+		if k, expected := MinimalRotation(s), naiveMinimalRotation(s); k != expected {
+			t.Fatalf("MinimalRotation(%q) = %d, expected %d", s, k, expected)
+		}
+	}
+}
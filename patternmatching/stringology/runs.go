@@ -0,0 +1,138 @@
+package stringology
+
+import (
+	"math/bits"
+	"sort"
+
+	"showmeyourcode/go/playground/patternmatching"
+)
+
+// Run is a maximal repetition: s[Start:End] has the shortest period Period, is at
+// least two periods long, and the period cannot be extended by a byte on either
+// side. "aabaabaa" holds the runs "aa" (twice), "aabaabaa" with period 3 and
+// others.
+type Run struct {
+	Start  int
+	End    int
+	Period int
+}
+
+// Exponent returns how many times the period fits in the run.
+func (r Run) Exponent() float64 {
+	return float64(r.End-r.Start) / float64(r.Period)
+}
+
+// Runs returns every run of s, ordered by start, then by end.
+//
+// By the runs theorem, the Lyndon root of every run, under one of the two
+// orders of the alphabet, is the longest Lyndon word starting at its position.
+// Those words come from the suffix array: the longest Lyndon word starting at i
+// ends where the next smaller suffix starts. Each is extended to both sides
+// with longest common extension queries answered from the LCP arrays of s and
+// its reverse, so there are fewer than 2n candidates, each checked in O(1)
+// after O(n log n) preprocessing.
+func Runs(s string) []Run {
+	n := len(s)
+	if n < 2 {
+		return nil
+	}
+	forward := newLCE(s)
+	reversed := make([]byte, n)
+	for i := range s {
+		reversed[n-1-i] = s[i]
+	}
+	backward := newLCE(string(reversed))
+
+	inverted := make([]byte, n)
+	for i := range s {
+		inverted[i] = 255 - s[i]
+	}
+	seen := make(map[Run]bool)
+	var runs []Run
+	for _, rank := range [][]int{forward.rank, newLCE(string(inverted)).rank} {
+		lyndon := longestLyndonWords(rank)
+		for i, j := range lyndon {
+			p := j - i
+			if j == n {
+				continue
+			}
+			end := j + forward.query(i, j)
+			start := i
+			if i > 0 {
+				start -= backward.query(n-i, n-j)
+			}
+			run := Run{Start: start, End: end, Period: p}
+			if end-start >= 2*p && !seen[run] {
+				seen[run] = true
+				runs = append(runs, run)
+			}
+		}
+	}
+	sort.Slice(runs, func(a, b int) bool {
+		if runs[a].Start != runs[b].Start {
+			return runs[a].Start < runs[b].Start
+		}
+		return runs[a].End < runs[b].End
+	})
+	return runs
+}
+
+// longestLyndonWords returns for every i the end of the longest Lyndon word
+// starting at i: the first j > i whose suffix is smaller, or n.
+func longestLyndonWords(rank []int) []int {
+	n := len(rank)
+	end := make([]int, n)
+	var stack []int
+	for i := n - 1; i >= 0; i-- {
+		for len(stack) > 0 && rank[stack[len(stack)-1]] > rank[i] {
+			stack = stack[:len(stack)-1]
+		}
+		end[i] = n
+		if len(stack) > 0 {
+			end[i] = stack[len(stack)-1]
+		}
+		stack = append(stack, i)
+	}
+	return end
+}
+
+// lce answers longest common extension queries: the length of the longest
+// common prefix of two suffixes, the minimum of the LCP array between their
+// ranks, found in a sparse table.
+type lce struct {
+	rank  []int
+	table [][]int
+}
+
+func newLCE(s string) *lce {
+	index := patternmatching.NewSuffixIndex(s)
+	sa := index.SuffixArray()
+	x := &lce{rank: make([]int, len(s)), table: [][]int{index.LCP()}}
+	for i, start := range sa {
+		x.rank[start] = i
+	}
+	for width := 1; 2*width <= len(s); width *= 2 {
+		previous := x.table[len(x.table)-1]
+		level := make([]int, len(s)-2*width+1)
+		for i := range level {
+			level[i] = min(previous[i], previous[i+width])
+		}
+		x.table = append(x.table, level)
+	}
+	return x
+}
+
+// query returns the longest common prefix of the suffixes at i and j; a position
+// at the end of the string has an empty suffix.
+func (x *lce) query(i, j int) int {
+	n := len(x.rank)
+	if i == n || j == n {
+		return 0
+	}
+	if i == j {
+		return n - i
+	}
+	lo, hi := min(x.rank[i], x.rank[j])+1, max(x.rank[i], x.rank[j])
+	level := bits.Len(uint(hi-lo+1)) - 1
+	return min(x.table[level][lo], x.table[level][hi-(1<<level)+1])
+}
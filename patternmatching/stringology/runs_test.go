@@ -0,0 +1,65 @@
+package stringology
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// naiveRuns extends every segment with period p as far as it goes and keeps
+// those at least 2p long whose shortest period is p.
+func naiveRuns(s string) []Run {
+	var runs []Run
+	for p := 1; 2*p <= len(s); p++ {
+		for start := 0; start+p < len(s); {
+			if s[start] != s[start+p] {
+				start++
+				continue
+			}
+			end := start + p
+			for end < len(s) && s[end] == s[end-p] {
+				end++
+			}
+			if end-start >= 2*p && Period(s[start:end]) == p {
+				runs = append(runs, Run{Start: start, End: end, Period: p})
+			}
+			start = end - p + 1
+		}
+	}
+	sort.Slice(runs, func(a, b int) bool {
+		if runs[a].Start != runs[b].Start {
+			return runs[a].Start < runs[b].Start
+		}
+		return runs[a].End < runs[b].End
+	})
+	return runs
+}
+
+func TestRuns(t *testing.T) {
+	expected := []Run{
+		{Start: 0, End: 2, Period: 1},
+		{Start: 0, End: 8, Period: 3},
+		{Start: 3, End: 5, Period: 1},
+		{Start: 6, End: 8, Period: 1},
+	}
+	if runs := Runs("aabaabaa"); !reflect.DeepEqual(runs, expected) {
+		t.Errorf("Runs(aabaabaa) = %v, expected %v", runs, expected)
+	}
+	if runs := Runs("abc"); runs != nil {
+		t.Errorf("Runs(abc) = %v, expected none", runs)
+	}
+	if e := (Run{Start: 0, End: 8, Period: 3}).Exponent(); e != 8.0/3 {
+		t.Errorf("Exponent() = %v, expected 8/3", e)
+	}
+}
+
+func TestRunsMatchBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		s := randomString(random, random.Intn(40), "abc"[:1+random.Intn(3)])
+		if runs, expected := Runs(s), naiveRuns(s); !reflect.DeepEqual(runs, expected) && len(runs)+len(expected) > 0 {
+			t.Fatalf("Runs(%q) = %v, expected %v", s, runs, expected)
+		}
+	}
+}
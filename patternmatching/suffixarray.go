@@ -0,0 +1,338 @@
+package patternmatching
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// SuffixIndex answers repeated queries over a fixed text.
+//
+// The suffix array lists the start offsets of all suffixes of the text in
+// lexicographic order, so the suffixes starting with a given pattern form one
+// contiguous block which can be found with two binary searches in O(m log n).
+// The LCP array stores, for every suffix in that order, the length of the
+// longest common prefix with the previous one.
+type SuffixIndex struct {
+	text string
+	sa   []int
+	lcp  []int
+}
+
+// NewSuffixIndex builds the suffix array of text by prefix doubling in O(n log n)
+// and the LCP array with Kasai's algorithm in O(n).
+func NewSuffixIndex(text string) *SuffixIndex {
+	sa := buildSuffixArray(text)
+	return &SuffixIndex{text: text, sa: sa, lcp: buildLCP(text, sa)}
+}
+
+// Text returns the indexed text.
+func (x *SuffixIndex) Text() string {
+	return x.text
+}
+
+// SuffixArray returns the start offsets of the suffixes in lexicographic order.
+func (x *SuffixIndex) SuffixArray() []int {
+	return append([]int(nil), x.sa...)
+}
+
+// LCP returns the longest common prefix of every suffix with its predecessor in
+// the suffix array. The first entry is always 0.
+func (x *SuffixIndex) LCP() []int {
+	return append([]int(nil), x.lcp...)
+}
+
+// Count returns the number of occurrences of pattern in the text.
+func (x *SuffixIndex) Count(pattern string) (int, MatchStats) {
+	var stats MatchStats
+	lo, hi := x.lookup(pattern, &stats)
+	return hi - lo, stats
+}
+
+// Locate returns every occurrence of pattern, in order of their start offsets.
+func (x *SuffixIndex) Locate(pattern string) ([]Match, MatchStats) {
+	var stats MatchStats
+	lo, hi := x.lookup(pattern, &stats)
+
+	starts := append([]int(nil), x.sa[lo:hi]...)
+	sort.Ints(starts)
+	matches := make([]Match, len(starts))
+	for i, start := range starts {
+		matches[i] = Match{Start: start, End: start + len(pattern)}
+	}
+	return matches, stats
+}
+
+// LongestRepeatedSubstring returns the longest substring occurring at least twice
+// in the text (the occurrences may overlap), or "" if no character repeats.
+func (x *SuffixIndex) LongestRepeatedSubstring() string {
+	best := 0
+	for i, length := range x.lcp {
+		if length > x.lcp[best] {
+			best = i
+		}
+	}
+	if len(x.lcp) == 0 || x.lcp[best] == 0 {
+		return ""
+	}
+	return x.text[x.sa[best] : x.sa[best]+x.lcp[best]]
+}
+
+// DistinctSubstrings returns the number of distinct non-empty substrings of the text.
+// Every suffix contributes its prefixes except those shared with the previous suffix.
+func (x *SuffixIndex) DistinctSubstrings() int {
+	n := len(x.text)
+	total := n * (n + 1) / 2
+	for _, length := range x.lcp {
+		total -= length
+	}
+	return total
+}
+
+// lookup returns the block sa[lo:hi] of suffixes starting with pattern.
+func (x *SuffixIndex) lookup(pattern string, stats *MatchStats) (int, int) {
+	if pattern == "" {
+		return 0, 0
+	}
+	lo := sort.Search(len(x.sa), func(i int) bool {
+		return x.comparePrefix(pattern, x.sa[i], stats) <= 0
+	})
+	hi := lo + sort.Search(len(x.sa)-lo, func(i int) bool {
+		return x.comparePrefix(pattern, x.sa[lo+i], stats) < 0
+	})
+	return lo, hi
+}
+
+// comparePrefix compares pattern with the first len(pattern) bytes of the suffix
+// starting at start. A suffix shorter than the pattern sorts before it.
+func (x *SuffixIndex) comparePrefix(pattern string, start int, stats *MatchStats) int {
+	suffix := x.text[start:]
+	for i := 0; i < len(pattern); i++ {
+		if i == len(suffix) {
+			return 1
+		}
+		stats.Comparisons++
+		if pattern[i] != suffix[i] {
+			if pattern[i] < suffix[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// buildSuffixArray sorts the suffixes by their first k characters for k = 1, 2, 4, ...
+// Each round sorts by the pair (rank of the first k characters, rank of the next k)
+// with two stable counting sorts, until all ranks are distinct.
+func buildSuffixArray(text string) []int {
+	n := len(text)
+	sa := make([]int, n)
+	if n == 0 {
+		return sa
+	}
+
+	rank := make([]int, n)
+	next := make([]int, n)
+	tmp := make([]int, n)
+	for i := 0; i < n; i++ {
+		sa[i] = i
+		// Rank 0 is reserved for suffixes shorter than the compared prefix.
+		rank[i] = int(text[i]) + 1
+	}
+	classes := 257
+
+	for k := 1; ; k <<= 1 {
+		second := func(i int) int {
+			if i+k < n {
+				return rank[i+k]
+			}
+			return 0
+		}
+		first := func(i int) int {
+			return rank[i]
+		}
+		countingSort(sa, tmp, second, classes)
+		countingSort(sa, tmp, first, classes)
+
+		next[sa[0]] = 1
+		for i := 1; i < n; i++ {
+			next[sa[i]] = next[sa[i-1]]
+			if first(sa[i]) != first(sa[i-1]) || second(sa[i]) != second(sa[i-1]) {
+				next[sa[i]]++
+			}
+		}
+		rank, next = next, rank
+
+		classes = rank[sa[n-1]] + 1
+		if rank[sa[n-1]] == n {
+			return sa
+		}
+	}
+}
+
+// countingSort stably sorts sa by key, where keys are in [0, classes).
+func countingSort(sa, tmp []int, key func(int) int, classes int) {
+	count := make([]int, classes+1)
+	for _, i := range sa {
+		count[key(i)+1]++
+	}
+	for c := 1; c <= classes; c++ {
+		count[c] += count[c-1]
+	}
+	for _, i := range sa {
+		tmp[count[key(i)]] = i
+		count[key(i)]++
+	}
+	copy(sa, tmp)
+}
+
+// buildLCP implements Kasai's algorithm. Walking the suffixes in text order, the
+// common prefix with the predecessor shrinks by at most one character per step,
+// so the total work is linear.
+func buildLCP(text string, sa []int) []int {
+	n := len(text)
+	lcp := make([]int, n)
+	rank := make([]int, n)
+	for i, start := range sa {
+		rank[start] = i
+	}
+
+	h := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[rank[i]-1]
+		for i+h < n && j+h < n && text[i+h] == text[j+h] {
+			h++
+		}
+		lcp[rank[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
+
+// suffixIndexMagic starts every serialized SuffixIndex, followed by a format version.
+const (
+	suffixIndexMagic   = "PMSA"
+	suffixIndexVersion = 1
+)
+
+var (
+	// ErrIndexTooLarge is returned when serializing a text longer than the format supports.
+	ErrIndexTooLarge = errors.New("patternmatching: index too large to serialize")
+	// ErrInvalidIndex is returned when reading data which is not a valid serialized index.
+	ErrInvalidIndex = errors.New("patternmatching: invalid index data")
+)
+
+// WriteTo serializes the index: a header, the text and the suffix array as
+// little-endian uint32 values. The LCP array is rebuilt when reading.
+func (x *SuffixIndex) WriteTo(w io.Writer) (int64, error) {
+	if uint64(len(x.text)) > math.MaxUint32 {
+		return 0, ErrIndexTooLarge
+	}
+
+	cw := &countingWriter{w: w}
+	header := make([]byte, 0, len(suffixIndexMagic)+1+4)
+	header = append(header, suffixIndexMagic...)
+	header = append(header, suffixIndexVersion)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(x.text)))
+	if _, err := cw.Write(header); err != nil {
+		return cw.n, err
+	}
+	if _, err := io.WriteString(cw, x.text); err != nil {
+		return cw.n, err
+	}
+
+	sa := make([]uint32, len(x.sa))
+	for i, start := range x.sa {
+		sa[i] = uint32(start)
+	}
+	err := binary.Write(cw, binary.LittleEndian, sa)
+	return cw.n, err
+}
+
+// ReadSuffixIndex loads an index written by SuffixIndex.WriteTo.
+func ReadSuffixIndex(r io.Reader) (*SuffixIndex, error) {
+	header := make([]byte, len(suffixIndexMagic)+1+4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIndex, err)
+	}
+	if string(header[:len(suffixIndexMagic)]) != suffixIndexMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidIndex)
+	}
+	if version := header[len(suffixIndexMagic)]; version != suffixIndexVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidIndex, version)
+	}
+	n := int(binary.LittleEndian.Uint32(header[len(suffixIndexMagic)+1:]))
+
+	// The length comes from the data, so the buffers only grow with what is
+	// actually read instead of being allocated up front.
+	text, err := readExactly(r, int64(n))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := readExactly(r, 4*int64(n))
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make([]int, n+1)
+	for i := range rank {
+		rank[i] = -1
+	}
+	sa := make([]int, n)
+	for i := range sa {
+		start := int(binary.LittleEndian.Uint32(raw[4*i:]))
+		if start >= n || rank[start] >= 0 {
+			return nil, fmt.Errorf("%w: suffix array is not a permutation", ErrInvalidIndex)
+		}
+		rank[start] = i
+		sa[i] = start
+	}
+	// Adjacent suffixes are in order when their first bytes are, or when these
+	// are equal and the suffixes one byte later are. The empty suffix at n ranks
+	// below all the others.
+	for i := 1; i < n; i++ {
+		a, b := sa[i-1], sa[i]
+		if text[a] > text[b] || text[a] == text[b] && rank[a+1] > rank[b+1] {
+			return nil, fmt.Errorf("%w: suffix array is not sorted", ErrInvalidIndex)
+		}
+	}
+
+	x := &SuffixIndex{text: string(text), sa: sa}
+	x.lcp = buildLCP(x.text, sa)
+	return x, nil
+}
+
+// readExactly reads n bytes from r, failing with ErrInvalidIndex when there are
+// fewer.
+func readExactly(r io.Reader, n int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, n))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIndex, err)
+	}
+	if int64(len(data)) != n {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIndex, io.ErrUnexpectedEOF)
+	}
+	return data, nil
+}
+
+// countingWriter counts the bytes written, as required by io.WriterTo.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,163 @@
+package patternmatching
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSuffixIndexBanana(t *testing.T) {
+	index := NewSuffixIndex("banana")
+
+	if sa := index.SuffixArray(); !reflect.DeepEqual(sa, []int{5, 3, 1, 0, 4, 2}) {
+		t.Errorf("unexpected suffix array %v", sa)
+	}
+	if lcp := index.LCP(); !reflect.DeepEqual(lcp, []int{0, 1, 3, 0, 0, 2}) {
+		t.Errorf("unexpected LCP array %v", lcp)
+	}
+	if lrs := index.LongestRepeatedSubstring(); lrs != "ana" {
+		t.Errorf("expected longest repeated substring \"ana\", got %q", lrs)
+	}
+	if distinct := index.DistinctSubstrings(); distinct != 15 {
+		t.Errorf("expected 15 distinct substrings, got %d", distinct)
+	}
+
+	matches, stats := index.Locate("ana")
+	if !reflect.DeepEqual(matches, []Match{{Start: 1, End: 4}, {Start: 3, End: 6}}) {
+		t.Errorf("unexpected matches %v", matches)
+	}
+	if stats.Comparisons == 0 {
+		t.Errorf("expected comparisons to be counted")
+	}
+}
+
+func TestSuffixIndexMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 50; round++ {
+		text := randomText(random, 1+random.Intn(300), "ab")
+		index := NewSuffixIndex(text)
+
+		if distinct := index.DistinctSubstrings(); distinct != countDistinctSubstrings(text) {
+			t.Fatalf("%q: expected %d distinct substrings, got %d", text, countDistinctSubstrings(text), distinct)
+		}
+
+		for query := 0; query < 20; query++ {
+			start := random.Intn(len(text))
+			pattern := text[start:min(len(text), start+1+random.Intn(6))]
+			if query%4 == 0 {
+				pattern = randomText(random, 1+random.Intn(6), "abc")
+			}
+
+			expected, _ := MustCompile(pattern, BruteForce).FindAll(text)
+			matches, _ := index.Locate(pattern)
+			if len(matches) != len(expected) || (len(expected) > 0 && !reflect.DeepEqual(matches, expected)) {
+				t.Fatalf("%q in %q: expected %v, got %v", pattern, text, expected, matches)
+			}
+			if count, _ := index.Count(pattern); count != len(expected) {
+				t.Fatalf("%q in %q: expected count %d, got %d", pattern, text, len(expected), count)
+			}
+		}
+	}
+}
+
+func TestSuffixIndexSerialization(t *testing.T) {
+	index := NewSuffixIndex("mississippi")
+
+	var buf bytes.Buffer
+	written, err := index.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %d bytes, wrote %d", written, buf.Len())
+	}
+
+	loaded, err := ReadSuffixIndex(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Text() != index.Text() || !reflect.DeepEqual(loaded.SuffixArray(), index.SuffixArray()) || !reflect.DeepEqual(loaded.LCP(), index.LCP()) {
+		t.Errorf("loaded index differs from the original")
+	}
+
+	corrupted := append([]byte(nil), buf.Bytes()...)
+	corrupted[len(corrupted)-1] = 0xff
+	if _, err := ReadSuffixIndex(bytes.NewReader(corrupted)); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected ErrInvalidIndex for a corrupted suffix array, got %v", err)
+	}
+	if _, err := ReadSuffixIndex(bytes.NewReader(buf.Bytes()[:10])); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected ErrInvalidIndex for truncated data, got %v", err)
+	}
+
+	// Swapping the first two suffixes keeps a permutation which is not sorted.
+	unsorted := append([]byte(nil), buf.Bytes()...)
+	sa := unsorted[len(unsorted)-4*len(index.Text()):]
+	for i := 0; i < 4; i++ {
+		sa[i], sa[4+i] = sa[4+i], sa[i]
+	}
+	if _, err := ReadSuffixIndex(bytes.NewReader(unsorted)); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected ErrInvalidIndex for an unsorted suffix array, got %v", err)
+	}
+
+	// A header claiming 4 GiB of text fails on the missing data.
+	huge := append([]byte(nil), buf.Bytes()...)
+	binary.LittleEndian.PutUint32(huge[len(suffixIndexMagic)+1:], math.MaxUint32)
+	if _, err := ReadSuffixIndex(bytes.NewReader(huge)); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected ErrInvalidIndex for a length beyond the data, got %v", err)
+	}
+}
+
+func TestSuffixIndexSerializationRoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		index := NewSuffixIndex(randomText(random, random.Intn(50), "ab"))
+		var buf bytes.Buffer
+		if _, err := index.WriteTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(index.Text()); n > 1 {
+			// Any two swapped suffixes are out of order.
+			swapped := append([]byte(nil), buf.Bytes()...)
+			sa := swapped[len(swapped)-4*n:]
+			i, j := random.Intn(n), random.Intn(n-1)
+			if j >= i {
+				j++
+			}
+			for k := 0; k < 4; k++ {
+				sa[4*i+k], sa[4*j+k] = sa[4*j+k], sa[4*i+k]
+			}
+			if _, err := ReadSuffixIndex(bytes.NewReader(swapped)); !errors.Is(err, ErrInvalidIndex) {
+				t.Fatalf("%q: expected ErrInvalidIndex after swapping suffixes %d and %d, got %v", index.Text(), i, j, err)
+			}
+		}
+		loaded, err := ReadSuffixIndex(&buf)
+		if err != nil {
+			t.Fatalf("%q: %v", index.Text(), err)
+		}
+		if !reflect.DeepEqual(loaded.SuffixArray(), index.SuffixArray()) {
+			t.Fatalf("%q: loaded index differs from the original", index.Text())
+		}
+	}
+}
+
+func randomText(random *rand.Rand, n int, alphabet string) string {
+	text := make([]byte, n)
+	for i := range text {
+		text[i] = alphabet[random.Intn(len(alphabet))]
+	}
+	return string(text)
+}
+
+func countDistinctSubstrings(text string) int {
+	seen := make(map[string]bool)
+	for i := 0; i < len(text); i++ {
+		for j := i + 1; j <= len(text); j++ {
+			seen[text[i:j]] = true
+		}
+	}
+	return len(seen)
+}
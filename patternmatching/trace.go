@@ -0,0 +1,206 @@
+package patternmatching
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrTraceUnsupported is returned by TraceSearch for matchers not created by this package.
+var ErrTraceUnsupported = errors.New("patternmatching: matcher cannot be traced")
+
+// EventKind tells what a trace event records.
+type EventKind string
+
+const (
+	// EventCompare is a comparison of a text character with a pattern character.
+	EventCompare EventKind = "compare"
+	// EventHash is a comparison of a window hash with the pattern hash.
+	EventHash EventKind = "hash"
+	// EventMatch is a reported match.
+	EventMatch EventKind = "match"
+	// EventShift is a move of the pattern to a new alignment.
+	EventShift EventKind = "shift"
+)
+
+// ShiftReason tells which rule decided the length of a shift.
+type ShiftReason string
+
+const (
+	// ShiftNext moves the pattern by one position.
+	ShiftNext ShiftReason = "next"
+	// ShiftLPS falls back to the longest proper prefix which is also a suffix
+	// of the matched part (Morris-Pratt, Knuth-Morris-Pratt).
+	ShiftLPS ShiftReason = "lps"
+	// ShiftBadCharacter aligns the mismatched text character with its last
+	// occurrence in the pattern.
+	ShiftBadCharacter ShiftReason = "bad-character"
+	// ShiftGoodSuffix aligns the matched suffix with its previous occurrence in the pattern.
+	ShiftGoodSuffix ShiftReason = "good-suffix"
+	// ShiftLastCharacter aligns the last byte of the window with its previous
+	// occurrence in the pattern (Horspool).
+	ShiftLastCharacter ShiftReason = "last-character"
+	// ShiftNextCharacter aligns the byte after the window with its last occurrence
+	// in the pattern (Sunday).
+	ShiftNextCharacter ShiftReason = "next-character"
+	// ShiftHashMismatch moves the window after its hash differed from the pattern hash.
+	ShiftHashMismatch ShiftReason = "hash-mismatch"
+	// ShiftAfterMatch moves the pattern past a whole match (Morris-Pratt, Boyer-Moore).
+	ShiftAfterMatch ShiftReason = "after-match"
+	// ShiftCriticalFactor moves the pattern past the characters of its right half
+	// matched before a mismatch (Two-Way).
+	ShiftCriticalFactor ShiftReason = "critical-factor"
+	// ShiftPeriod moves the pattern by its period once the right half matched (Two-Way).
+	ShiftPeriod ShiftReason = "period"
+	// ShiftFailureLink follows a failure link to the longest suffix of the matched
+	// part which is a prefix of a pattern (Aho-Corasick).
+	ShiftFailureLink ShiftReason = "failure-link"
+)
+
+// Event is one step of a traced search.
+//
+// Alignment is the text offset the first pattern character is aligned with. For
+// compare events TextIndex and PatternIndex are the compared positions and Equal
+// the outcome; hash events set Equal when the hashes are the same. Shift events
+// carry the new alignment, the distance moved and the rule deciding it.
+//
+// The compared positions and the outcome are always exported to JSON, so that a
+// mismatch at index 0 is not mistaken for a missing comparison.
+type Event struct {
+	Step         int         `json:"step"`
+	Kind         EventKind   `json:"kind"`
+	Alignment    int         `json:"alignment"`
+	TextIndex    int         `json:"text_index"`
+	PatternIndex int         `json:"pattern_index"`
+	Equal        bool        `json:"equal"`
+	Shift        int         `json:"shift,omitempty"`
+	Reason       ShiftReason `json:"reason,omitempty"`
+}
+
+// Trace records every step of one search. It can be exported with encoding/json
+// or drawn with WriteDiagram.
+//
+// Text and Pattern are what the algorithm actually compared, i.e. after the
+// Unicode options of the matcher were applied.
+type Trace struct {
+	Algorithm Algorithm  `json:"algorithm"`
+	Text      string     `json:"text"`
+	Pattern   string     `json:"pattern"`
+	Events    []Event    `json:"events"`
+	Stats     MatchStats `json:"stats"`
+}
+
+func (t *Trace) add(event Event) {
+	event.Step = len(t.Events) + 1
+	t.Events = append(t.Events, event)
+}
+
+// TraceSearch searches text with m and records every step. Brute force,
+// Morris-Pratt, Knuth-Morris-Pratt, both Karp-Rabin variants, both Boyer-Moore
+// variants, Horspool, Sunday, Aho-Corasick, the Z-algorithm and Two-Way record
+// comparisons and shifts; the wildcard matchers record only their matches.
+//
+// Tracing allocates an event per comparison, so it is meant for teaching and
+// debugging on short inputs.
+func TraceSearch(m Matcher, text string) (*Trace, error) {
+	mt, ok := m.(*matcher)
+	if !ok {
+		return nil, ErrTraceUnsupported
+	}
+	if mt.opts.unicode() {
+		text = transform(text, mt.opts).text
+	}
+
+	trace := &Trace{Algorithm: mt.algo, Text: text, Pattern: mt.needle}
+	// Matches are recorded once they pass the same filter as in FindIter, so the
+	// trace holds the matches FindAll returns.
+	record := func(match Match) bool {
+		trace.add(Event{Kind: EventMatch, Alignment: match.Start})
+		return true
+	}
+	if mt.opts.Overlap == NonOverlapping {
+		record = nonOverlapping(record)
+	}
+	s := search{stats: mt.scanner.tables().stats(), tracer: trace, yield: func(start int) bool {
+		return record(Match{Start: start, End: start + mt.width})
+	}}
+	mt.scanner.scan(text, &s)
+	trace.Stats = s.stats
+	return trace, nil
+}
+
+// WriteDiagram draws every step of the trace as the text with the pattern aligned
+// below it and a marker under the compared characters:
+//
+//	step 3: compare text[5] 'S' with pattern[1] 'I': equal
+//	THIS IS A SIMPLE EXAMPLE
+//	    SIMPLE
+//	     ^
+//
+// Bytes that are not printable ASCII are drawn as '.'.
+func (t *Trace) WriteDiagram(w io.Writer) error {
+	text := printable(t.Text)
+	pattern := printable(t.Pattern)
+
+	for _, event := range t.Events {
+		var marker string
+		switch event.Kind {
+		case EventCompare:
+			outcome := "mismatch"
+			if event.Equal {
+				outcome = "equal"
+			}
+			if _, err := fmt.Fprintf(w, "step %d: compare text[%d] %q with pattern[%d] %q: %s\n",
+				event.Step, event.TextIndex, t.Text[event.TextIndex],
+				event.PatternIndex, t.Pattern[event.PatternIndex], outcome); err != nil {
+				return err
+			}
+			marker = strings.Repeat(" ", event.TextIndex) + "^"
+		case EventHash:
+			outcome := "different"
+			if event.Equal {
+				outcome = "equal"
+			}
+			if _, err := fmt.Fprintf(w, "step %d: compare hash of text[%d:%d]: %s\n",
+				event.Step, event.Alignment, event.Alignment+len(t.Pattern), outcome); err != nil {
+				return err
+			}
+			marker = strings.Repeat(" ", event.Alignment) + strings.Repeat("^", len(t.Pattern))
+		case EventMatch:
+			if _, err := fmt.Fprintf(w, "step %d: match at %d\n", event.Step, event.Alignment); err != nil {
+				return err
+			}
+			marker = strings.Repeat(" ", event.Alignment) + strings.Repeat("=", len(t.Pattern))
+		case EventShift:
+			if _, err := fmt.Fprintf(w, "step %d: shift by %d (%s) to %d\n",
+				event.Step, event.Shift, event.Reason, event.Alignment); err != nil {
+				return err
+			}
+			if event.Alignment+len(t.Pattern) > len(t.Text) {
+				// The pattern no longer fits, the search is over.
+				continue
+			}
+		}
+
+		lines := []string{text, strings.Repeat(" ", event.Alignment) + pattern}
+		if marker != "" {
+			lines = append(lines, marker)
+		}
+		if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printable replaces the bytes which would break the alignment of the diagram.
+func printable(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c < ' ' || c > '~' {
+			b[i] = '.'
+		}
+	}
+	return string(b)
+}
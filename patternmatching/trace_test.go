@@ -0,0 +1,194 @@
+package patternmatching
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTraceAgreesWithSearch(t *testing.T) {
+	text := "ABABDABACDABABCABABABABCABAB"
+	pattern := "ABABCABAB"
+
+	for _, algo := range Algorithms() {
+		t.Run(string(algo), func(t *testing.T) {
+			m := MustCompile(pattern, algo)
+			matches, stats := m.FindAll(text)
+			trace, err := TraceSearch(m, text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if trace.Stats != stats {
+				t.Errorf("trace stats %+v, search stats %+v", trace.Stats, stats)
+			}
+
+			var starts []int
+			compared := false
+			for i, event := range trace.Events {
+				if event.Step != i+1 {
+					t.Fatalf("event %d has step %d", i, event.Step)
+				}
+				switch event.Kind {
+				case EventMatch:
+					starts = append(starts, event.Alignment)
+				case EventCompare:
+					compared = true
+					if event.TextIndex != event.Alignment+event.PatternIndex {
+						t.Errorf("compare event %+v is not at its alignment", event)
+					}
+					if event.Equal != (text[event.TextIndex] == pattern[event.PatternIndex]) {
+						t.Errorf("compare event %+v has the wrong outcome", event)
+					}
+				case EventShift:
+					if event.Shift <= 0 || event.Reason == "" {
+						t.Errorf("invalid shift event %+v", event)
+					}
+				}
+			}
+			var expected []int
+			for _, match := range matches {
+				expected = append(expected, match.Start)
+			}
+			if !reflect.DeepEqual(starts, expected) {
+				t.Errorf("traced matches %v, search matches %v", starts, expected)
+			}
+			if algo != KarpRabin && algo != KarpRabin64 && !compared {
+				t.Error("no comparisons were traced")
+			}
+		})
+	}
+}
+
+func TestTraceNonOverlapping(t *testing.T) {
+	for _, algo := range Algorithms() {
+		m := MustCompile("AA", algo, WithOverlap(NonOverlapping))
+		matches, stats := m.FindAll("AAAA")
+		trace, err := TraceSearch(m, "AAAA")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var starts, expected []int
+		for _, event := range trace.Events {
+			if event.Kind == EventMatch {
+				starts = append(starts, event.Alignment)
+			}
+		}
+		for _, match := range matches {
+			expected = append(expected, match.Start)
+		}
+		if !reflect.DeepEqual(starts, expected) || !reflect.DeepEqual(expected, []int{0, 2}) || trace.Stats != stats {
+			t.Errorf("%s: traced matches %v and %+v, search matches %v and %+v", algo, starts, trace.Stats, expected, stats)
+		}
+	}
+}
+
+func TestTraceShiftReasons(t *testing.T) {
+	tests := []struct {
+		algo     Algorithm
+		text     string
+		pattern  string
+		expected []ShiftReason
+	}{
+		{algo: BruteForce, text: "ABC", pattern: "B", expected: []ShiftReason{ShiftNext, ShiftNext, ShiftNext}},
+		{algo: KnuthMorrisPratt, text: "AAB", pattern: "AB", expected: []ShiftReason{ShiftLPS, ShiftLPS}},
+		{algo: MorrisPratt, text: "ABAB", pattern: "AB", expected: []ShiftReason{ShiftAfterMatch, ShiftAfterMatch}},
+		{algo: BoyerMoore, text: "XXXAB", pattern: "AB", expected: []ShiftReason{ShiftBadCharacter, ShiftBadCharacter, ShiftBadCharacter, ShiftAfterMatch}},
+		{algo: BoyerMooreOptimized, text: "ABXAB", pattern: "AB", expected: []ShiftReason{ShiftGoodSuffix, ShiftBadCharacter, ShiftGoodSuffix}},
+		{algo: KarpRabin, text: "XAB", pattern: "AB", expected: []ShiftReason{ShiftHashMismatch, ShiftNext}},
+		{algo: AhoCorasick, text: "XAAB", pattern: "AB", expected: []ShiftReason{ShiftNext, ShiftFailureLink}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.algo), func(t *testing.T) {
+			trace, err := TraceSearch(MustCompile(tt.pattern, tt.algo), tt.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var reasons []ShiftReason
+			for _, event := range trace.Events {
+				if event.Kind == EventShift {
+					reasons = append(reasons, event.Reason)
+				}
+			}
+			if !reflect.DeepEqual(reasons, tt.expected) {
+				t.Errorf("expected shifts %v, got %v", tt.expected, reasons)
+			}
+		})
+	}
+}
+
+func TestTraceWriteDiagram(t *testing.T) {
+	trace, err := TraceSearch(MustCompile("SIMPLE", BoyerMooreOptimized), "THIS IS A SIMPLE EXAMPLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := trace.WriteDiagram(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	diagram := out.String()
+	for _, expected := range []string{
+		"step 1: compare text[5] 'I' with pattern[5] 'E': mismatch\nTHIS IS A SIMPLE EXAMPLE\nSIMPLE\n     ^\n",
+		"match at 10\nTHIS IS A SIMPLE EXAMPLE\n          SIMPLE\n          ======\n",
+	} {
+		if !strings.Contains(diagram, expected) {
+			t.Errorf("diagram does not contain %q:\n%s", expected, diagram)
+		}
+	}
+}
+
+func TestTraceJSON(t *testing.T) {
+	trace, err := TraceSearch(MustCompile("AB", KnuthMorrisPratt), "AAB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Trace
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, trace) {
+		t.Errorf("JSON round trip changed the trace:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"reason":"lps"`) {
+		t.Errorf("JSON does not contain the shift reason:\n%s", data)
+	}
+
+	// The first step compares text[0] with pattern[0] and finds a mismatch.
+	trace, err = TraceSearch(MustCompile("AB", KnuthMorrisPratt), "BAB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields struct {
+		Events []map[string]any `json:"events"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	first := fields.Events[0]
+	if first["kind"] != "compare" || first["text_index"] != 0.0 || first["pattern_index"] != 0.0 || first["equal"] != false {
+		t.Errorf("a mismatch at index 0 does not survive the export: %v", first)
+	}
+}
+
+type externalMatcher struct {
+	Matcher
+}
+
+func TestTraceUnsupportedMatcher(t *testing.T) {
+	_, err := TraceSearch(externalMatcher{MustCompile("A", BruteForce)}, "A")
+	if !errors.Is(err, ErrTraceUnsupported) {
+		t.Errorf("expected ErrTraceUnsupported, got %v", err)
+	}
+}
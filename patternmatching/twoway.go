@@ -0,0 +1,128 @@
+package patternmatching
+
+// twoWay is the Crochemore-Perrin Two-Way algorithm.
+//
+// The pattern is split at a critical factorization pattern[:ell+1], pattern[ell+1:].
+// Every alignment first matches the right half from left to right; a mismatch
+// there moves the pattern past the matched characters. When the right half
+// matches, the left half is matched from right to left and the pattern moves by
+// its period. The search is linear in the worst case and needs only a constant
+// amount of memory besides the pattern.
+type twoWay struct {
+	pattern string
+	// ell is the last position of the left half, -1 when it is empty.
+	ell int
+	// period is the period of the pattern in the periodic case, otherwise a shift
+	// no occurrence can be skipped by.
+	period int
+	// periodic tells whether the left half occurs again one period later, in which
+	// case the part known to match after a shift is remembered.
+	periodic bool
+	preprocessing
+}
+
+// Two-Way Search
+func newTwoWay(pattern string) scanner {
+	m := len(pattern)
+	ell1, period1, comparisons1 := maximalSuffix(pattern, false)
+	ell2, period2, comparisons2 := maximalSuffix(pattern, true)
+	ell, period := ell2, period2
+	if ell1 > ell2 {
+		ell, period = ell1, period1
+	}
+
+	// The factorization costs comparisons but no tables; checking whether the left
+	// half repeats one period later counts as ell+1 more.
+	t := twoWay{pattern: pattern, ell: ell, period: period, preprocessing: preprocessing{cost: comparisons1 + comparisons2}}
+	if ell+1+period <= m {
+		t.cost += ell + 1
+	}
+	if ell+1+period <= m && pattern[:ell+1] == pattern[period:period+ell+1] {
+		t.periodic = true
+	} else {
+		t.period = max(ell+1, m-ell-1) + 1
+	}
+	return t
+}
+
+func (t twoWay) scan(text string, s *search) {
+	m := len(t.pattern)
+	n := len(text)
+
+	// memory is the last pattern position known to match after a shift by the period.
+	memory := -1
+	for j := 0; j <= n-m; {
+		// Right half, left to right.
+		i := t.ell + 1
+		if t.periodic {
+			i = max(t.ell, memory) + 1
+		}
+		for i < m && t.matches(text, j, i, s) {
+			i++
+		}
+		if i < m {
+			s.shift(j, j+i-t.ell, ShiftCriticalFactor)
+			j += i - t.ell
+			memory = -1
+			continue
+		}
+
+		// Left half, right to left.
+		i = t.ell
+		for i > memory && t.matches(text, j, i, s) {
+			i--
+		}
+		if i <= memory && !s.report(j) {
+			return
+		}
+		s.shift(j, j+t.period, ShiftPeriod)
+		j += t.period
+		if t.periodic {
+			memory = m - t.period - 1
+		}
+	}
+}
+
+// matches compares pattern[i] with the text aligned at j.
+func (t twoWay) matches(text string, j, i int, s *search) bool {
+	s.stats.Comparisons++
+	equal := text[j+i] == t.pattern[i]
+	s.compare(j, j+i, i, equal)
+	return equal
+}
+
+// maximalSuffix returns the start of the maximal suffix of pattern minus one, its
+// period and the number of comparisons, for the byte order or, with reversed set,
+// the reversed byte order. The larger of the two starts gives a critical
+// factorization.
+func maximalSuffix(pattern string, reversed bool) (int, int, int) {
+	ms, j, k, period := -1, 0, 1, 1
+	comparisons := 0
+	for j+k < len(pattern) {
+		comparisons++
+		a, b := pattern[j+k], pattern[ms+k]
+		if reversed {
+			a, b = b, a
+		}
+		switch {
+		case a < b:
+			// The suffix at ms stays maximal and the period grows to the whole factor.
+			j += k
+			k = 1
+			period = j - ms
+		case a == b:
+			if k != period {
+				k++
+			} else {
+				j += period
+				k = 1
+			}
+		default:
+			// A larger suffix starts at j.
+			ms = j
+			j = ms + 1
+			k, period = 1, 1
+		}
+	}
+	return ms, period, comparisons
+}
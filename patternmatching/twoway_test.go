@@ -0,0 +1,48 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTwoWayMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for _, alphabet := range []string{"a", "ab", "abc", "ACGT"} {
+		for round := 0; round < 200; round++ {
+			text := randomText(random, random.Intn(500), alphabet)
+			pattern := randomText(random, 1+random.Intn(12), alphabet)
+
+			expected, _ := MustCompile(pattern, BruteForce).FindAll(text)
+			matches, stats := MustCompile(pattern, TwoWay).FindAll(text)
+			if !reflect.DeepEqual(matches, expected) {
+				t.Fatalf("%q in %q: expected %v, got %v", pattern, text, expected, matches)
+			}
+			if stats.Comparisons > 2*len(text) {
+				t.Fatalf("%q in %q: %d comparisons are not linear", pattern, text, stats.Comparisons)
+			}
+		}
+	}
+}
+
+func TestTwoWayCriticalFactorization(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		ell      int
+		period   int
+		periodic bool
+	}{
+		{pattern: "a", ell: -1, period: 1, periodic: true},
+		{pattern: "aaaa", ell: -1, period: 1, periodic: true},
+		{pattern: "abab", ell: 0, period: 2, periodic: true},
+		{pattern: "GCAGAGAG", ell: 1, period: 7, periodic: false},
+	}
+
+	for _, tt := range tests {
+		tw := newTwoWay(tt.pattern).(twoWay)
+		if tw.ell != tt.ell || tw.period != tt.period || tw.periodic != tt.periodic {
+			t.Errorf("%q: expected ell %d, period %d, periodic %v, got %d, %d, %v",
+				tt.pattern, tt.ell, tt.period, tt.periodic, tw.ell, tw.period, tw.periodic)
+		}
+	}
+}
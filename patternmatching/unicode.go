@@ -0,0 +1,159 @@
+package patternmatching
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options holds the matching modes selected with Option values.
+//
+// By default the algorithms compare bytes and report matches overlapping or not
+// as described for OverlapDefault. The Unicode modes transform both the
+// pattern and the text before searching, so they apply to every algorithm alike,
+// and map the matches back to offsets in the original text.
+type Options struct {
+	// Runes only reports matches starting and ending on rune boundaries, and
+	// reports rune offsets as well as byte offsets.
+	Runes bool
+	// FoldCase treats runes equal under Unicode simple case folding as equal,
+	// e.g. "k", "K" and the Kelvin sign "K". It implies Runes.
+	FoldCase bool
+	// Normalize compares the pattern and the text in Unicode Normalization Form C,
+	// so precomposed and decomposed accents match each other. It implies Runes.
+	Normalize bool
+	// Overlap selects whether the reported matches may overlap.
+	Overlap Overlap
+}
+
+// Option selects a matching mode for Compile.
+type Option func(*Options)
+
+// WithRunes restricts matches to whole runes and reports rune offsets.
+func WithRunes() Option {
+	return func(o *Options) { o.Runes = true }
+}
+
+// WithCaseFolding makes the search case-insensitive using Unicode simple case folding.
+func WithCaseFolding() Option {
+	return func(o *Options) { o.FoldCase = true }
+}
+
+// WithNormalization compares the pattern and the text after NFC normalization.
+func WithNormalization() Option {
+	return func(o *Options) { o.Normalize = true }
+}
+
+// unicode tells whether the pattern and the texts have to be transformed.
+func (o Options) unicode() bool {
+	return o.Runes || o.FoldCase || o.Normalize
+}
+
+// transformedText is a text prepared for a Unicode search, with a way back to
+// offsets in the original text.
+type transformedText struct {
+	text     string
+	segments []segment
+}
+
+// segment is a piece of the original text transformed as a whole: a single rune,
+// or with normalization a starter rune followed by its combining marks.
+type segment struct {
+	// start and end are the offsets of the transformed piece in the transformed text.
+	start, end int
+	// original is the position of the piece in the original text.
+	original Match
+}
+
+// transform applies the options to text. Bytes which are not valid UTF-8 are
+// kept as they are and form a segment of their own.
+func transform(text string, opts Options) transformedText {
+	var b strings.Builder
+	b.Grow(len(text))
+	segments := make([]segment, 0, len(text))
+	runes := 0
+
+	add := func(start, end int, piece string) {
+		count := utf8.RuneCountInString(text[start:end])
+		seg := segment{
+			start:    b.Len(),
+			original: Match{Start: start, End: end, RuneStart: runes, RuneEnd: runes + count},
+		}
+		if opts.FoldCase {
+			writeFolded(&b, piece)
+		} else {
+			b.WriteString(piece)
+		}
+		seg.end = b.Len()
+		segments = append(segments, seg)
+		runes += count
+	}
+
+	if opts.Normalize {
+		var it norm.Iter
+		it.InitString(norm.NFC, text)
+		for !it.Done() {
+			start := it.Pos()
+			piece := string(it.Next())
+			add(start, it.Pos(), piece)
+		}
+	} else {
+		for start := 0; start < len(text); {
+			_, size := utf8.DecodeRuneInString(text[start:])
+			add(start, start+size, text[start:start+size])
+			start += size
+		}
+	}
+
+	return transformedText{text: b.String(), segments: segments}
+}
+
+// writeFolded writes every rune of piece replaced by the representative of its
+// case folding orbit.
+func writeFolded(b *strings.Builder, piece string) {
+	for i := 0; i < len(piece); {
+		r, size := utf8.DecodeRuneInString(piece[i:])
+		if r == utf8.RuneError && size == 1 {
+			b.WriteByte(piece[i])
+		} else {
+			b.WriteRune(foldRune(r))
+		}
+		i += size
+	}
+}
+
+// foldRune returns the smallest rune of the orbit of r under unicode.SimpleFold,
+// which is the same for all runes equal under simple case folding.
+func foldRune(r rune) rune {
+	folded := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < folded {
+			folded = f
+		}
+	}
+	return folded
+}
+
+// original maps the match text[start:end] of the transformed text back to the
+// original text. Matches which do not start and end on segment boundaries are
+// rejected.
+func (t transformedText) original(start, end int) (Match, bool) {
+	first := sort.Search(len(t.segments), func(i int) bool { return t.segments[i].start >= start })
+	if first == len(t.segments) || t.segments[first].start != start {
+		return Match{}, false
+	}
+	last := first + sort.Search(len(t.segments)-first, func(i int) bool { return t.segments[first+i].end >= end })
+	if last == len(t.segments) || t.segments[last].end != end {
+		return Match{}, false
+	}
+
+	return Match{
+		Start:     t.segments[first].original.Start,
+		End:       t.segments[last].original.End,
+		RuneStart: t.segments[first].original.RuneStart,
+		RuneEnd:   t.segments[last].original.RuneEnd,
+	}, true
+}
@@ -0,0 +1,98 @@
+package patternmatching
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnicodeModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		pattern  string
+		opts     []Option
+		expected []Match
+	}{
+		{
+			name:     "rune offsets",
+			text:     "日本語テキスト",
+			pattern:  "テキ",
+			opts:     []Option{WithRunes()},
+			expected: []Match{{Start: 9, End: 15, RuneStart: 3, RuneEnd: 5}},
+		},
+		{
+			name:    "case folding",
+			text:    "GRÜN, grün, GrÜn",
+			pattern: "grün",
+			opts:    []Option{WithCaseFolding()},
+			expected: []Match{
+				{Start: 0, End: 5, RuneStart: 0, RuneEnd: 4},
+				{Start: 7, End: 12, RuneStart: 6, RuneEnd: 10},
+				{Start: 14, End: 19, RuneStart: 12, RuneEnd: 16},
+			},
+		},
+		{
+			name:     "case folding across byte lengths",
+			text:     "5 K", // Kelvin sign, three bytes in UTF-8
+			pattern:  "k",
+			opts:     []Option{WithCaseFolding()},
+			expected: []Match{{Start: 2, End: 5, RuneStart: 2, RuneEnd: 3}},
+		},
+		{
+			name:     "normalization",
+			text:     "le café noir", // decomposed e + combining acute accent
+			pattern:  "café",          // precomposed é
+			opts:     []Option{WithNormalization()},
+			expected: []Match{{Start: 3, End: 9, RuneStart: 3, RuneEnd: 8}},
+		},
+		{
+			name:     "normalization and case folding",
+			text:     "CAFÉ",
+			pattern:  "café",
+			opts:     []Option{WithNormalization(), WithCaseFolding()},
+			expected: []Match{{Start: 0, End: 6, RuneStart: 0, RuneEnd: 5}},
+		},
+		{
+			name:     "no match inside a rune",
+			text:     "é",
+			pattern:  "\xa9", // the second byte of é
+			opts:     []Option{WithRunes()},
+			expected: nil,
+		},
+		{
+			name:     "no match on a base character without its accent",
+			text:     "café",
+			pattern:  "cafe",
+			opts:     []Option{WithNormalization()},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		for _, algo := range Algorithms() {
+			t.Run(tt.name+"/"+string(algo), func(t *testing.T) {
+				matches, _ := MustCompile(tt.pattern, algo, tt.opts...).FindAll(tt.text)
+				if !reflect.DeepEqual(matches, tt.expected) {
+					t.Errorf("expected %v, got %v", tt.expected, matches)
+				}
+			})
+		}
+	}
+}
+
+func TestByteModeMatchesInsideRunes(t *testing.T) {
+	matches, _ := MustCompile("\xa9", KnuthMorrisPratt).FindAll("é")
+	if !reflect.DeepEqual(matches, []Match{{Start: 1, End: 2}}) {
+		t.Errorf("expected the byte search to match the second byte of é, got %v", matches)
+	}
+}
+
+func TestCompileRecordsOptions(t *testing.T) {
+	m := MustCompile("abc", BoyerMoore, WithCaseFolding(), WithNormalization())
+	if expected := (Options{FoldCase: true, Normalize: true}); m.Options() != expected {
+		t.Errorf("expected %+v, got %+v", expected, m.Options())
+	}
+	if m.Pattern() != "abc" {
+		t.Errorf("expected the original pattern, got %q", m.Pattern())
+	}
+}
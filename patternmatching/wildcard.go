@@ -0,0 +1,214 @@
+package patternmatching
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Wildcard algorithms accepted by CompileWildcard.
+const (
+	WildcardBruteForce Algorithm = "wildcard-brute-force"
+	WildcardShiftAnd   Algorithm = "wildcard-shift-and"
+)
+
+// ErrInvalidWildcard is returned by CompileWildcard for malformed patterns.
+var ErrInvalidWildcard = errors.New("patternmatching: invalid wildcard pattern")
+
+// CompileWildcard prepares a pattern with don't-care characters. Every position of
+// the pattern matches exactly one byte of the text:
+//
+//	?        any byte
+//	[CT]     one of the listed bytes; ranges such as [a-z] are allowed
+//	[^CT]    any byte except the listed ones
+//	\?       the next character literally
+//	A        the byte itself
+//
+// WildcardBruteForce checks every alignment, testing each text byte against the
+// set of bytes allowed at that pattern position. WildcardShiftAnd processes the
+// text in a single pass with one bit per pattern position, in the same way as
+// BitapHamming with no errors. Both report overlapping matches by default; of
+// the options only WithOverlap applies.
+func CompileWildcard(pattern string, algo Algorithm, opts ...Option) (Matcher, error) {
+	options, err := byteOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := parseWildcard(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var s scanner
+	switch algo {
+	case WildcardBruteForce:
+		// Every position keeps a set of 256 bits.
+		s = wildcardBruteForce{positions: positions, preprocessing: preprocessing{cost: len(positions), memory: len(positions) * 32}}
+	case WildcardShiftAnd:
+		s = newWildcardShiftAnd(positions)
+	default:
+		return nil, fmt.Errorf("patternmatching: unknown wildcard algorithm %q", algo)
+	}
+	return &matcher{pattern: pattern, algo: algo, opts: options, needle: pattern, width: len(positions), scanner: s}, nil
+}
+
+// byteSet is the set of bytes allowed at a pattern position.
+type byteSet [4]uint64
+
+func (b *byteSet) add(c byte) {
+	b[c/64] |= 1 << (c % 64)
+}
+
+func (b *byteSet) has(c byte) bool {
+	return b[c/64]&(1<<(c%64)) != 0
+}
+
+func (b *byteSet) invert() {
+	for i := range b {
+		b[i] = ^b[i]
+	}
+}
+
+// parseWildcard returns the set of allowed bytes for every pattern position.
+func parseWildcard(pattern string) ([]byteSet, error) {
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+
+	var positions []byteSet
+	for i := 0; i < len(pattern); i++ {
+		var set byteSet
+		switch pattern[i] {
+		case '?':
+			set.invert()
+		case '\\':
+			if i+1 == len(pattern) {
+				return nil, fmt.Errorf("%w: trailing backslash", ErrInvalidWildcard)
+			}
+			i++
+			set.add(pattern[i])
+		case '[':
+			end, err := parseClass(pattern, i, &set)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+		default:
+			set.add(pattern[i])
+		}
+		positions = append(positions, set)
+	}
+	return positions, nil
+}
+
+// parseClass parses the class starting at pattern[start] == '[' into set and
+// returns the offset of its closing bracket.
+func parseClass(pattern string, start int, set *byteSet) (int, error) {
+	i := start + 1
+	negated := i < len(pattern) && pattern[i] == '^'
+	if negated {
+		i++
+	}
+
+	empty := true
+	for ; i < len(pattern) && pattern[i] != ']'; i++ {
+		c := pattern[i]
+		if c == '\\' && i+1 < len(pattern) {
+			i++
+			c = pattern[i]
+		}
+		if i+2 < len(pattern) && pattern[i+1] == '-' && pattern[i+2] != ']' {
+			last := pattern[i+2]
+			if last < c {
+				return 0, fmt.Errorf("%w: invalid range %c-%c", ErrInvalidWildcard, c, last)
+			}
+			for r := int(c); r <= int(last); r++ {
+				set.add(byte(r))
+			}
+			i += 2
+		} else {
+			set.add(c)
+		}
+		empty = false
+	}
+
+	if i == len(pattern) {
+		return 0, fmt.Errorf("%w: missing ]", ErrInvalidWildcard)
+	}
+	if empty {
+		return 0, fmt.Errorf("%w: empty class", ErrInvalidWildcard)
+	}
+	if negated {
+		set.invert()
+	}
+	return i, nil
+}
+
+// wildcardBruteForce tests the pattern at every position, like bruteForce, with
+// a set membership test instead of a byte comparison.
+type wildcardBruteForce struct {
+	positions []byteSet
+	preprocessing
+}
+
+func (w wildcardBruteForce) scan(text string, s *search) {
+	m := len(w.positions)
+	n := len(text)
+
+	for i := 0; i <= n-m; i++ {
+		match := true
+		for j := 0; j < m; j++ {
+			s.stats.Comparisons++
+			if !w.positions[j].has(text[i+j]) {
+				match = false
+				break
+			}
+		}
+		if match && !s.report(i) {
+			return
+		}
+		// Wildcard searches are not traced, so the shift is only counted.
+		s.stats.Shifts++
+		s.stats.ShiftDistance++
+	}
+}
+
+// wildcardShiftAnd keeps one bit per pattern position: bit j is set when the
+// first j+1 positions match the text ending at the current byte. Bit j of
+// masks[c] is set when position j allows c, so wildcards cost nothing extra.
+type wildcardShiftAnd struct {
+	m     int
+	masks [256]bitVector
+	preprocessing
+}
+
+// newWildcardShiftAnd tests every byte against every position to build the masks.
+func newWildcardShiftAnd(positions []byteSet) wildcardShiftAnd {
+	words := (len(positions) + 63) / 64
+	w := wildcardShiftAnd{m: len(positions), preprocessing: preprocessing{cost: 256 * len(positions), memory: 256 * words * 8}}
+	for c := range w.masks {
+		w.masks[c] = newBitVector(words)
+		for j, set := range positions {
+			if set.has(byte(c)) {
+				w.masks[c].set(j)
+			}
+		}
+	}
+	return w
+}
+
+// scan counts one comparison per text byte, as every byte updates all pattern
+// positions at once, and one shift, as the pattern ends one byte later.
+func (w wildcardShiftAnd) scan(text string, s *search) {
+	state := newBitVector(len(w.masks[0]))
+	for i := 0; i < len(text); i++ {
+		s.stats.Comparisons++
+		if i > 0 {
+			s.stats.Shifts++
+			s.stats.ShiftDistance++
+		}
+		state.shiftInsertAnd(state, w.masks[text[i]])
+		if state.has(w.m-1) && !s.report(i-w.m+1) {
+			return
+		}
+	}
+}
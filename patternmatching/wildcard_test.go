@@ -0,0 +1,95 @@
+package patternmatching
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var wildcardAlgorithms = []Algorithm{WildcardBruteForce, WildcardShiftAnd}
+
+func TestWildcardMatching(t *testing.T) {
+	tests := []struct {
+		text     string
+		pattern  string
+		expected []int
+	}{
+		{text: "AAGCATGTACGA", pattern: "A?G[CT]", expected: []int{0, 4}},
+		{text: "user 42 logged in, user 7 logged out", pattern: "user [0-9]? logged", expected: []int{0}},
+		{text: "cat cot cut", pattern: "c[^u]t", expected: []int{0, 4}},
+		{text: "what? why?", pattern: "y\\?", expected: []int{8}},
+		{text: "ACGT", pattern: "????", expected: []int{0}},
+		{text: "ACGT", pattern: "?????", expected: nil},
+	}
+
+	for _, tt := range tests {
+		for _, algo := range wildcardAlgorithms {
+			t.Run(string(algo)+"/"+tt.pattern, func(t *testing.T) {
+				matcher, err := CompileWildcard(tt.pattern, algo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				matches, _ := matcher.FindAll(tt.text)
+				var starts []int
+				for _, match := range matches {
+					starts = append(starts, match.Start)
+				}
+				if !reflect.DeepEqual(starts, tt.expected) {
+					t.Errorf("expected matches at %v, got %v", tt.expected, starts)
+				}
+			})
+		}
+	}
+}
+
+func TestWildcardAlgorithmsAgree(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 100; round++ {
+		text := randomText(random, random.Intn(300), "ACGT")
+		var pattern strings.Builder
+		for i := 0; i < 1+random.Intn(90); i++ {
+			switch random.Intn(4) {
+			case 0:
+				pattern.WriteByte('?')
+			case 1:
+				pattern.WriteString("[CT]")
+			default:
+				pattern.WriteString(randomText(random, 1, "ACGT"))
+			}
+		}
+
+		bruteForce, _ := mustCompileWildcard(t, pattern.String(), WildcardBruteForce).FindAll(text)
+		shiftAnd, stats := mustCompileWildcard(t, pattern.String(), WildcardShiftAnd).FindAll(text)
+		if !reflect.DeepEqual(bruteForce, shiftAnd) {
+			t.Fatalf("%q in %q: brute force found %v, shift-and %v", pattern.String(), text, bruteForce, shiftAnd)
+		}
+		if stats.Comparisons != len(text) {
+			t.Fatalf("expected one shift-and comparison per text byte, got %d", stats.Comparisons)
+		}
+	}
+}
+
+func TestCompileWildcardErrors(t *testing.T) {
+	for _, pattern := range []string{"A[CT", "A[]", "A\\", "[z-a]"} {
+		if _, err := CompileWildcard(pattern, WildcardShiftAnd); !errors.Is(err, ErrInvalidWildcard) {
+			t.Errorf("%q: expected ErrInvalidWildcard, got %v", pattern, err)
+		}
+	}
+	if _, err := CompileWildcard("", WildcardShiftAnd); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("expected ErrEmptyPattern, got %v", err)
+	}
+	if _, err := CompileWildcard("A?", KnuthMorrisPratt); err == nil {
+		t.Errorf("expected an error for a non-wildcard algorithm")
+	}
+}
+
+func mustCompileWildcard(t *testing.T, pattern string, algo Algorithm) Matcher {
+	t.Helper()
+	matcher, err := CompileWildcard(pattern, algo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matcher
+}
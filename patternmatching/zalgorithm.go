@@ -0,0 +1,97 @@
+package patternmatching
+
+// zAlgorithm implements the Z-algorithm on strings.
+type zAlgorithm struct {
+	zSearch[byte]
+}
+
+// zSearch computes, for every text position, the length of the longest common
+// prefix of the pattern and the text starting there. A match is a position where
+// that length reaches the pattern length. It searches strings and slices.
+//
+// Like the Z array of a single string, the scan keeps the rightmost Z-box
+// text[l:r] equal to pattern[:r-l]. Inside the box the length is read from the
+// Z array of the pattern, and characters are only compared beyond r, so every
+// text character is matched at most once and the search is linear.
+type zSearch[T comparable] struct {
+	pattern []T
+	// z[k] is the length of the longest common prefix of pattern and pattern[k:].
+	z []int
+	preprocessing
+}
+
+// Z-Algorithm Search
+func newZAlgorithm(pattern string) scanner {
+	return zAlgorithm{newZSearch([]byte(pattern))}
+}
+
+func newZSearch[T comparable](pattern []T) zSearch[T] {
+	z, comparisons := computeZ(pattern)
+	return zSearch[T]{pattern: pattern, z: z, preprocessing: preprocessing{cost: comparisons, memory: len(z) * intBytes}}
+}
+
+func (za zAlgorithm) scan(text string, s *search) {
+	za.find([]byte(text), s)
+}
+
+func (za zSearch[T]) find(text []T, s *search) {
+	m := len(za.pattern)
+	n := len(text)
+
+	l, r := 0, 0
+	for i := 0; i <= n-m; i++ {
+		length := 0
+		if i < r {
+			length = min(za.z[i-l], r-i)
+		}
+		// Compare only when the Z-box does not decide the length on its own.
+		if i >= r || za.z[i-l] >= r-i {
+			for length < m {
+				s.stats.Comparisons++
+				equal := text[i+length] == za.pattern[length]
+				s.compare(i, i+length, length, equal)
+				if !equal {
+					break
+				}
+				length++
+			}
+			if i+length > r {
+				l, r = i, i+length
+			}
+		}
+		if length == m && !s.report(i) {
+			return
+		}
+		s.shift(i, i+1, ShiftNext)
+	}
+}
+
+// computeZ returns the Z array of s, with z[0] = len(s), and the number of
+// comparisons it took.
+func computeZ[T comparable](s []T) ([]int, int) {
+	n := len(s)
+	z := make([]int, n)
+	comparisons := 0
+	if n == 0 {
+		return z, 0
+	}
+	z[0] = n
+
+	l, r := 0, 0
+	for i := 1; i < n; i++ {
+		if i < r {
+			z[i] = min(z[i-l], r-i)
+		}
+		for i+z[i] < n {
+			comparisons++
+			if s[z[i]] != s[i+z[i]] {
+				break
+			}
+			z[i]++
+		}
+		if i+z[i] > r {
+			l, r = i, i+z[i]
+		}
+	}
+	return z, comparisons
+}
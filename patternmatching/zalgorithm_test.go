@@ -0,0 +1,42 @@
+package patternmatching
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestComputeZ(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		s := randomText(random, random.Intn(40), "ab")
+		expected := make([]int, len(s))
+		for k := range s {
+			for k+expected[k] < len(s) && s[expected[k]] == s[k+expected[k]] {
+				expected[k]++
+			}
+		}
+		if got, _ := computeZ([]byte(s)); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("Z array of %q: expected %v, got %v", s, expected, got)
+		}
+	}
+}
+
+func TestZAlgorithmMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for round := 0; round < 200; round++ {
+		text := randomText(random, random.Intn(500), "ab")
+		pattern := randomText(random, 1+random.Intn(12), "ab")
+
+		expected, _ := MustCompile(pattern, BruteForce).FindAll(text)
+		matches, stats := MustCompile(pattern, ZAlgorithm).FindAll(text)
+		if !reflect.DeepEqual(matches, expected) {
+			t.Fatalf("%q in %q: expected %v, got %v", pattern, text, expected, matches)
+		}
+		// Every character extends the Z-box at most once and every alignment
+		// stops with at most one mismatch.
+		if stats.Comparisons > 2*len(text) {
+			t.Fatalf("%q in %q: %d comparisons are not linear", pattern, text, stats.Comparisons)
+		}
+	}
+}
@@ -14,6 +14,11 @@ type testCase struct {
 	expectedComparisonsKarpRabin           int
 	expectedComparisonsBoyerMoore          int
 	expectedComparisonsOptimizedBoyerMoore int
+	expectedComparisonsHorspool            int
+	expectedComparisonsSunday              int
+	expectedComparisonsAhoCorasick         int
+	expectedComparisonsZAlgorithm          int
+	expectedComparisonsTwoWay              int
 }
 
 func TestStringMatchingAlgorithms(t *testing.T) {
@@ -29,6 +34,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           19, // early hash mismatch
 			expectedComparisonsBoyerMoore:          16, // jumps efficiently
 			expectedComparisonsOptimizedBoyerMoore: 14, // jumps efficiently
+			expectedComparisonsHorspool:            14,
+			expectedComparisonsSunday:              11,
+			expectedComparisonsAhoCorasick:         27,
+			expectedComparisonsZAlgorithm:          21,
+			expectedComparisonsTwoWay:              23,
 		},
 		{
 			text:                                   "AAAAAAAAAAH",
@@ -40,6 +50,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           7,
 			expectedComparisonsBoyerMoore:          12, // skips most
 			expectedComparisonsOptimizedBoyerMoore: 11, // skips most
+			expectedComparisonsHorspool:            11,
+			expectedComparisonsSunday:              20,
+			expectedComparisonsAhoCorasick:         17,
+			expectedComparisonsZAlgorithm:          17,
+			expectedComparisonsTwoWay:              11,
 		},
 		{
 			text:                                   "THIS IS MY NEW STRING AAAAHHHH",
@@ -51,6 +66,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           28,
 			expectedComparisonsBoyerMoore:          17,
 			expectedComparisonsOptimizedBoyerMoore: 12,
+			expectedComparisonsHorspool:            12,
+			expectedComparisonsSunday:              11,
+			expectedComparisonsAhoCorasick:         32,
+			expectedComparisonsZAlgorithm:          29,
+			expectedComparisonsTwoWay:              28,
 		},
 		{
 			text:                                   "THIS TEST WILL HAVE MULTIPLE MATCHES, SO THAT WE CAN TEST. ONE MORE TEST.",
@@ -62,6 +82,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           70,
 			expectedComparisonsBoyerMoore:          42,
 			expectedComparisonsOptimizedBoyerMoore: 33,
+			expectedComparisonsHorspool:            33,
+			expectedComparisonsSunday:              26,
+			expectedComparisonsAhoCorasick:         84,
+			expectedComparisonsZAlgorithm:          79,
+			expectedComparisonsTwoWay:              74,
 		},
 		{
 			text:                                   "COMPUTER SCIENCE IS NO MORE ABOUT COMPUTERS THAN ASTRONOMY IS ABOUT TELESCOPES",
@@ -73,6 +98,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           77,
 			expectedComparisonsBoyerMoore:          86,
 			expectedComparisonsOptimizedBoyerMoore: 45,
+			expectedComparisonsHorspool:            45,
+			expectedComparisonsSunday:              31,
+			expectedComparisonsAhoCorasick:         82,
+			expectedComparisonsZAlgorithm:          79,
+			expectedComparisonsTwoWay:              77,
 		},
 		{
 			text:                                   "NO COMPUTER IS EVER GOING TO ASK A NEW, REASONABLE QUESTION. IT TAKES TRAINED PEOPLE TO DO THAT.",
@@ -84,6 +114,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           90,
 			expectedComparisonsBoyerMoore:          27,
 			expectedComparisonsOptimizedBoyerMoore: 22,
+			expectedComparisonsHorspool:            22,
+			expectedComparisonsSunday:              24,
+			expectedComparisonsAhoCorasick:         105,
+			expectedComparisonsZAlgorithm:          96,
+			expectedComparisonsTwoWay:              91,
 		},
 		{
 			text:                                   "WE CAN ONLY SEE A SHORT DISTANCE AHEAD, BUT WE CAN SEE PLENTY THERE THAT NEEDS TO BE DONE.",
@@ -95,6 +130,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           83,
 			expectedComparisonsBoyerMoore:          27,
 			expectedComparisonsOptimizedBoyerMoore: 22,
+			expectedComparisonsHorspool:            22,
+			expectedComparisonsSunday:              22,
+			expectedComparisonsAhoCorasick:         94,
+			expectedComparisonsZAlgorithm:          85,
+			expectedComparisonsTwoWay:              86,
 		},
 		{
 			text:                                   "QAZQAZQAZQAZZQAZZQAZZQAZZZZZZZZQQQQQQQZZZZZQAQAQAQAQAZZZQAAAAAZZZQAZZZZZZZQQQQQAAAZZZ",
@@ -106,6 +146,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           81,
 			expectedComparisonsBoyerMoore:          63,
 			expectedComparisonsOptimizedBoyerMoore: 55,
+			expectedComparisonsHorspool:            100,
+			expectedComparisonsSunday:              69,
+			expectedComparisonsAhoCorasick:         111,
+			expectedComparisonsZAlgorithm:          104,
+			expectedComparisonsTwoWay:              89,
 		},
 		{
 			text:                                   "COMPUTER SCIENCE IS THE OPERATING SYSTEM FOR ALL INNOVATION.",
@@ -117,6 +162,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           57,
 			expectedComparisonsBoyerMoore:          24,
 			expectedComparisonsOptimizedBoyerMoore: 19,
+			expectedComparisonsHorspool:            19,
+			expectedComparisonsSunday:              17,
+			expectedComparisonsAhoCorasick:         65,
+			expectedComparisonsZAlgorithm:          60,
+			expectedComparisonsTwoWay:              53,
 		},
 		{
 			text:                                   "WWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWWKWWWWWKWWWWK",
@@ -128,6 +178,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           57, // some hash collisions
 			expectedComparisonsBoyerMoore:          63,
 			expectedComparisonsOptimizedBoyerMoore: 60,
+			expectedComparisonsHorspool:            60,
+			expectedComparisonsSunday:              116,
+			expectedComparisonsAhoCorasick:         110,
+			expectedComparisonsZAlgorithm:          108,
+			expectedComparisonsTwoWay:              60,
 		},
 		{
 			text:                                   "aaaaaaabaaaaaaabaaaaaaabaaaaaaabaaaaaaabmatchhereaaaaaaab",
@@ -139,6 +194,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           45,  // lots of hash collisions
 			expectedComparisonsBoyerMoore:          22,  // skips huge chunks
 			expectedComparisonsOptimizedBoyerMoore: 21,
+			expectedComparisonsHorspool:            21,
+			expectedComparisonsSunday:              72,
+			expectedComparisonsAhoCorasick:         62,
+			expectedComparisonsZAlgorithm:          49,
+			expectedComparisonsTwoWay:              47,
 		},
 		{
 			text:                                   "ababababababababababababababababx",
@@ -150,6 +210,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           19, // some hash checks needed
 			expectedComparisonsBoyerMoore:          25, // some clever skipping
 			expectedComparisonsOptimizedBoyerMoore: 24,
+			expectedComparisonsHorspool:            24,
+			expectedComparisonsSunday:              150,
+			expectedComparisonsAhoCorasick:         42,
+			expectedComparisonsZAlgorithm:          42,
+			expectedComparisonsTwoWay:              33,
 		},
 		{
 			text:                                   "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab",
@@ -161,6 +226,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           1,  // one hash match
 			expectedComparisonsBoyerMoore:          39, // one big jump to match
 			expectedComparisonsOptimizedBoyerMoore: 38,
+			expectedComparisonsHorspool:            38,
+			expectedComparisonsSunday:              38,
+			expectedComparisonsAhoCorasick:         38,
+			expectedComparisonsZAlgorithm:          38,
+			expectedComparisonsTwoWay:              38,
 		},
 		{
 			text:                                   "lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod",
@@ -172,6 +242,11 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			expectedComparisonsKarpRabin:           63,
 			expectedComparisonsBoyerMoore:          21,
 			expectedComparisonsOptimizedBoyerMoore: 19,
+			expectedComparisonsHorspool:            19,
+			expectedComparisonsSunday:              19,
+			expectedComparisonsAhoCorasick:         75,
+			expectedComparisonsZAlgorithm:          75,
+			expectedComparisonsTwoWay:              54,
 		},
 		// {
 		// 	text:                                   "ababcabcababcabcababcabcabcabcabcabcabcabcabcabcabcababcabc",
@@ -224,6 +299,59 @@ func TestStringMatchingAlgorithms(t *testing.T) {
 			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsOptimizedBoyerMoore {
 				t.Errorf("BoyerMooreOptimized: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsBoyerMoore, matches, comparisons)
 			}
+
+			// Test Boyer-Moore-Horspool algorithm
+			matches, comparisons = Horspool(tt.text, tt.pattern)
+			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsHorspool {
+				t.Errorf("Horspool: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsHorspool, matches, comparisons)
+			}
+
+			// Test Sunday algorithm
+			matches, comparisons = Sunday(tt.text, tt.pattern)
+			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsSunday {
+				t.Errorf("Sunday: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsSunday, matches, comparisons)
+			}
+
+			// Test Aho-Corasick algorithm
+			matches, comparisons = AhoCorasick(tt.text, tt.pattern)
+			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsAhoCorasick {
+				t.Errorf("AhoCorasick: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsAhoCorasick, matches, comparisons)
+			}
+
+			// Test Z-algorithm
+			matches, comparisons = ZAlgorithm(tt.text, tt.pattern)
+			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsZAlgorithm {
+				t.Errorf("ZAlgorithm: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsZAlgorithm, matches, comparisons)
+			}
+
+			// Test Two-Way algorithm
+			matches, comparisons = TwoWay(tt.text, tt.pattern)
+			if matches != tt.expectedMatches || comparisons != tt.expectedComparisonsTwoWay {
+				t.Errorf("TwoWay: Expected %d matches and %d comparisons, got %d matches and %d comparisons", tt.expectedMatches, tt.expectedComparisonsTwoWay, matches, comparisons)
+			}
+		})
+	}
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name                string
+		a, b                string
+		expectedChanges     int
+		expectedComparisons int
+	}{
+		{"Equal texts", "a\nb\nc\n", "a\nb\nc\n", 0, 3},
+		{"One changed line", "a\nb\nc\n", "a\nx\nc\n", 2, 5},
+		{"Appended line", "a\nb\n", "a\nb\nc\n", 1, 2},
+		{"Empty texts", "", "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, comparisons := LineDiff(tt.a, tt.b)
+			if changes != tt.expectedChanges || comparisons != tt.expectedComparisons {
+				t.Errorf("LineDiff: Expected %d changes and %d comparisons, got %d changes and %d comparisons", tt.expectedChanges, tt.expectedComparisons, changes, comparisons)
+			}
 		})
 	}
 }